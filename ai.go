@@ -0,0 +1,245 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// AIItem is a file handed to Box AI.
+type AIItem struct {
+	Id      string `json:"id"`                // The id of the file.
+	Type    string `json:"type"`              // Type of the item, always file.
+	Content string `json:"content,omitempty"` // The content to use instead of the file content.
+}
+
+// AIResponse is the answer of Box AI to a free form request.
+type AIResponse struct {
+	Answer           string   `json:"answer,omitempty"`            // The answer provided by Box AI.
+	CreatedAt        *BoxTime `json:"created_at,omitempty"`        // When the answer was created.
+	CompletionReason string   `json:"completion_reason,omitempty"` // Why the answer ended, e.g. done.
+}
+
+// AIStructuredResponse is the answer of Box AI to a structured
+// extraction, keyed by field key.
+type AIStructuredResponse struct {
+	Answer           map[string]interface{} `json:"answer,omitempty"`            // The extracted values keyed by field key.
+	CreatedAt        *BoxTime               `json:"created_at,omitempty"`        // When the answer was created.
+	CompletionReason string                 `json:"completion_reason,omitempty"` // Why the answer ended, e.g. done.
+}
+
+// AIExtractField describes a single value to extract.
+type AIExtractField struct {
+	Key         string          `json:"key"`                   // The key the value is returned under.
+	Type        string          `json:"type,omitempty"`        // The type of the value (string, float, date, enum, multiSelect).
+	Description string          `json:"description,omitempty"` // The description of the field.
+	DisplayName string          `json:"displayName,omitempty"` // The display name of the field.
+	Prompt      string          `json:"prompt,omitempty"`      // Context helping Box AI to find the value.
+	Options     []AIFieldOption `json:"options,omitempty"`     // The possible values of enum and multiSelect fields.
+}
+
+type AIFieldOption struct {
+	Key string `json:"key"` // The value of the option.
+}
+
+// AIMetadataTemplate names a metadata template whose fields are
+// extracted.
+type AIMetadataTemplate struct {
+	TemplateKey string `json:"template_key"`    // The key of the template.
+	Type        string `json:"type,omitempty"`  // Type of the object, always metadata_template.
+	Scope       string `json:"scope,omitempty"` // The scope of the template, e.g. enterprise.
+}
+
+// AIAgent overrides the configuration of the Box AI agent answering a
+// request. Either Id names a preconfigured agent, or the tools to be
+// tuned are set; tools left nil keep their default configuration. Type
+// is filled in by the call when empty.
+type AIAgent struct {
+	Type           string       `json:"type,omitempty"`             // The kind of agent, e.g. ai_agent_ask or ai_agent_id.
+	Id             string       `json:"id,omitempty"`               // The id of a preconfigured agent.
+	BasicText      *AIAgentTool `json:"basic_text,omitempty"`       // The tool used for short texts.
+	LongText       *AIAgentTool `json:"long_text,omitempty"`        // The tool used for long texts.
+	BasicTextMulti *AIAgentTool `json:"basic_text_multi,omitempty"` // The tool used for short texts of several items (ask only).
+	LongTextMulti  *AIAgentTool `json:"long_text_multi,omitempty"`  // The tool used for long texts of several items (ask only).
+	BasicGen       *AIAgentTool `json:"basic_gen,omitempty"`        // The tool used for text generation (text_gen only).
+}
+
+// AIAgentTool is the configuration of a single tool of an agent.
+type AIAgentTool struct {
+	Model                  string       `json:"model,omitempty"`                     // The model used by the tool.
+	PromptTemplate         string       `json:"prompt_template,omitempty"`           // The template of the prompt sent to the model.
+	SystemMessage          string       `json:"system_message,omitempty"`            // The system message sent to the model.
+	ContentTemplate        string       `json:"content_template,omitempty"`          // How the content is passed to the model (text_gen only).
+	NumTokensForCompletion int          `json:"num_tokens_for_completion,omitempty"` // The maximum number of tokens of the answer.
+	LLMEndpointParams      *AILLMParams `json:"llm_endpoint_params,omitempty"`       // Parameters of the model endpoint.
+}
+
+// AILLMParams are the parameters of the model endpoint. Pointers are
+// left nil to keep the default.
+type AILLMParams struct {
+	Type        string   `json:"type"`                  // The kind of endpoint, e.g. openai_params or google_params.
+	Temperature *float64 `json:"temperature,omitempty"` // The sampling temperature.
+	TopP        *float64 `json:"top_p,omitempty"`       // The nucleus sampling probability.
+	TopK        *float64 `json:"top_k,omitempty"`       // The number of candidate tokens (google and aws only).
+}
+
+// aiRequest is the request body of the AI endpoints.
+type aiRequest struct {
+	Mode     string              `json:"mode,omitempty"`
+	Prompt   string              `json:"prompt,omitempty"`
+	Items    []AIItem            `json:"items"`
+	Template *AIMetadataTemplate `json:"metadata_template,omitempty"`
+	Fields   []AIExtractField    `json:"fields,omitempty"`
+	Agent    *AIAgent            `json:"ai_agent,omitempty"`
+}
+
+// aiAgent returns a copy of agent with its type filled in, or nil when
+// no agent is given.
+func aiAgent(agent *AIAgent, kind string) *AIAgent {
+	if agent == nil {
+		return nil
+	}
+	a := *agent
+	if a.Type == "" {
+		if a.Id != "" {
+			a.Type = "ai_agent_id"
+		} else {
+			a.Type = kind
+		}
+	}
+	return &a
+}
+
+// doAI posts the request to the AI endpoint at path and decodes the
+// answer into resp.
+func (box *Box) doAI(ctx context.Context, path string, req *aiRequest, resp interface{}) error {
+	reqBody, _ := json.Marshal(req)
+
+	body, err := box.doRequest(ctx, "POST", path, nil, reqBody)
+	if err != nil {
+		return err
+	}
+
+	return box.decode(body, resp)
+}
+
+// aiItems converts the files to the items of an AI request.
+func aiItems(files []*File) ([]AIItem, error) {
+	if len(files) == 0 {
+		return nil, errors.New("No files while using AI")
+	}
+	items := make([]AIItem, len(files))
+	for i, f := range files {
+		if f.Id == "" {
+			return nil, errors.New("Empty file id while using AI")
+		}
+		items[i] = AIItem{Id: f.Id, Type: "file"}
+	}
+	return items, nil
+}
+
+// AIAsk asks Box AI a question about the files. The agent may be nil
+// to use the default configuration. Note that only Id is required
+// apriori for the files.
+func (box *Box) AIAsk(ctx context.Context, prompt string, agent *AIAgent, files []*File, options ...RequestOption) (*AIResponse, error) {
+	ctx = withCall(ctx, options)
+	if prompt == "" {
+		return nil, errors.New("Empty prompt while using AIAsk")
+	}
+	items, err := aiItems(files)
+	if err != nil {
+		return nil, err
+	}
+
+	req := aiRequest{Mode: "single_item_qa", Prompt: prompt, Items: items,
+		Agent: aiAgent(agent, "ai_agent_ask")}
+	if len(items) > 1 {
+		req.Mode = "multiple_item_qa"
+	}
+
+	var resp AIResponse
+	if err = box.doAI(ctx, "ai/ask", &req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AITextGen generates text for the file from the prompt, e.g. to draft
+// or rewrite its content. The agent may be nil to use the default
+// configuration. Note that only Id is required apriori for the file.
+func (box *Box) AITextGen(ctx context.Context, prompt string, agent *AIAgent, file *File, options ...RequestOption) (*AIResponse, error) {
+	ctx = withCall(ctx, options)
+	if prompt == "" {
+		return nil, errors.New("Empty prompt while using AITextGen")
+	}
+	items, err := aiItems([]*File{file})
+	if err != nil {
+		return nil, err
+	}
+
+	req := aiRequest{Prompt: prompt, Items: items,
+		Agent: aiAgent(agent, "ai_agent_text_gen")}
+
+	var resp AIResponse
+	if err = box.doAI(ctx, "ai/text_gen", &req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AIExtract extracts the information described by prompt from the
+// files as free form text. The prompt may be a question or a list of
+// keys such as "title, author, date". The agent may be nil to use the
+// default configuration. Note that only Id is required apriori for the
+// files.
+func (box *Box) AIExtract(ctx context.Context, prompt string, agent *AIAgent, files []*File, options ...RequestOption) (*AIResponse, error) {
+	ctx = withCall(ctx, options)
+	if prompt == "" {
+		return nil, errors.New("Empty prompt while using AIExtract")
+	}
+	items, err := aiItems(files)
+	if err != nil {
+		return nil, err
+	}
+
+	req := aiRequest{Prompt: prompt, Items: items,
+		Agent: aiAgent(agent, "ai_agent_extract")}
+
+	var resp AIResponse
+	if err = box.doAI(ctx, "ai/extract", &req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AIExtractStructured extracts the values of either the fields of the
+// metadata template or the given fields from the files. The answer can
+// be written back to the files as metadata. Exactly one of template
+// and fields must be given. The agent may be nil to use the default
+// configuration. Note that only Id is required apriori for the files.
+func (box *Box) AIExtractStructured(ctx context.Context, template *AIMetadataTemplate, fields []AIExtractField, agent *AIAgent, files []*File, options ...RequestOption) (*AIStructuredResponse, error) {
+	ctx = withCall(ctx, options)
+	if (template == nil) == (len(fields) == 0) {
+		return nil, errors.New("Either template or fields required while using AIExtractStructured")
+	}
+	items, err := aiItems(files)
+	if err != nil {
+		return nil, err
+	}
+
+	req := aiRequest{Items: items, Fields: fields,
+		Agent: aiAgent(agent, "ai_agent_extract_structured")}
+	if template != nil {
+		t := *template
+		if t.Type == "" {
+			t.Type = "metadata_template"
+		}
+		req.Template = &t
+	}
+
+	var resp AIStructuredResponse
+	if err = box.doAI(ctx, "ai/extract_structured", &req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,89 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// AppItem is an item of an application integrated with Box.
+type AppItem struct {
+	Id              string `json:"id,omitempty"`               // The id of the app item.
+	Type            string `json:"type,omitempty"`             // Type of the object, always app_item.
+	ApplicationType string `json:"application_type,omitempty"` // The type of the application owning the app item.
+}
+
+// AppItemAssociation links an app item to a file or a folder.
+type AppItemAssociation struct {
+	Id      string   `json:"id,omitempty"`       // The id of the association.
+	Type    string   `json:"type,omitempty"`     // Type of the object, always app_item_association.
+	AppItem *AppItem `json:"app_item,omitempty"` // The associated app item.
+	Item    *Entity  `json:"item,omitempty"`     // The file or folder the app item is associated with.
+}
+
+// AppItemAssociations returns all app items associated with the
+// file. Note that only Id is required apriori.
+func (f *File) AppItemAssociations(ctx context.Context, box *Box, options ...RequestOption) ([]AppItemAssociation, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using AppItemAssociations")
+	}
+	return appItemAssociations(ctx, box, fmt.Sprintf("files/%s/app_item_associations", f.Id))
+}
+
+// AppItemAssociations returns all app items associated with the
+// folder. Note that only Id is required apriori.
+func (f *Folder) AppItemAssociations(ctx context.Context, box *Box, options ...RequestOption) ([]AppItemAssociation, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using AppItemAssociations")
+	}
+	return appItemAssociations(ctx, box, fmt.Sprintf("folders/%s/app_item_associations", f.Id))
+}
+
+// AppItemAssociationsPage returns a single page of the app items
+// associated with the file with its paging information. Note that only
+// Id is required apriori.
+func (f *File) AppItemAssociationsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[AppItemAssociation], error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using AppItemAssociationsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	rawurl := fmt.Sprintf("files/%s/app_item_associations", f.Id)
+	return listPage[AppItemAssociation](ctx, box, rawurl, opts.markerValues(), nil)
+}
+
+// AppItemAssociationsPage returns a single page of the app items
+// associated with the folder with its paging information. Note that
+// only Id is required apriori.
+func (f *Folder) AppItemAssociationsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[AppItemAssociation], error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using AppItemAssociationsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	rawurl := fmt.Sprintf("folders/%s/app_item_associations", f.Id)
+	return listPage[AppItemAssociation](ctx, box, rawurl, opts.markerValues(), nil)
+}
+
+// appItemAssociations fetches all pages of the associations listed at
+// rawurl.
+func appItemAssociations(ctx context.Context, box *Box, rawurl string) ([]AppItemAssociation, error) {
+	var all []AppItemAssociation
+	err := box.listAll(ctx, rawurl, nil, nil, func(entries json.RawMessage) error {
+		var page []AppItemAssociation
+		err := box.decode(entries, &page)
+		all = append(all, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
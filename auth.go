@@ -0,0 +1,484 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/oauth2"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Endpoint is the oauth2 endpoint of Box.
+var Endpoint = oauth2.Endpoint{
+	AuthURL:  "https://account.box.com/api/oauth2/authorize",
+	TokenURL: "https://api.box.com/oauth2/token",
+}
+
+// RevokeURL is the oauth2 token revocation endpoint of Box.
+var RevokeURL = "https://api.box.com/oauth2/revoke"
+
+// SetAppInfo adds oauth2 app info
+func (box *Box) SetAppInfo(clientid, clientsecret string) error {
+	if clientid == "" || clientsecret == "" {
+		return errors.New("Empty client id or secret while using SetAppInfo")
+	}
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.config = &oauth2.Config{
+		ClientID:     clientid,
+		ClientSecret: clientsecret,
+		Endpoint:     Endpoint,
+	}
+	return nil
+}
+
+// SetAccessToken sets access token to avoid calling Auth method. The
+// token is used as is and never refreshed, see SetToken.
+func (box *Box) SetAccessToken(accesstoken string) {
+	box.SetToken(&oauth2.Token{AccessToken: accesstoken, TokenType: "Bearer"})
+}
+
+// SetToken sets the token used by all subsequent requests. When it
+// carries a refresh token and the app info is set, it is refreshed
+// automatically once expired.
+func (box *Box) SetToken(token *oauth2.Token) {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.token = token
+}
+
+// SetTokenStore makes the box save the tokens it obtains, by Auth or
+// by refreshing, to store, and starts using the token already saved
+// there if any. A box resuming from a saved refresh token does not need
+// to go through Auth again.
+func (box *Box) SetTokenStore(store TokenStore) error {
+	token, err := store.Load()
+	if err != nil {
+		return err
+	}
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.store = store
+	if token != nil {
+		box.token = token
+	}
+	return nil
+}
+
+// saveToken sets a newly obtained token, saves it to the token store,
+// if any, and notifies OnTokenRefresh.
+func (box *Box) saveToken(token *oauth2.Token) error {
+	box.mu.Lock()
+	box.token = token
+	store := box.store
+	box.mu.Unlock()
+	if store != nil {
+		if err := store.Save(token); err != nil {
+			return err
+		}
+	}
+	if box.OnTokenRefresh != nil {
+		box.OnTokenRefresh(token)
+	}
+	return nil
+}
+
+// SetTokenSource makes the box get its tokens from the given source
+// instead of managing them itself, e.g. to share tokens between
+// processes. The source must be safe for concurrent use.
+func (box *Box) SetTokenSource(source oauth2.TokenSource) {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.source = source
+	box.renew = nil
+}
+
+// tokenRenewer gets new tokens without a refresh token, e.g. with a
+// JWT assertion. The token request is bound to ctx, the context of the
+// request needing the token.
+type tokenRenewer interface {
+	renewToken(ctx context.Context) (*oauth2.Token, error)
+}
+
+// setRenewingSource makes the box get its tokens from renew, keeping
+// them until they expire or Box refuses them.
+func (box *Box) setRenewingSource(renew tokenRenewer) {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.source = nil
+	box.renew = renew
+}
+
+// SetAsUser makes the box act on behalf of the given managed user in
+// all subsequent requests, uploads and downloads included, by sending
+// the As-User header. It requires an admin or a service account token
+// allowed to impersonate users. An empty id stops the impersonation.
+func (box *Box) SetAsUser(userId string) {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.asUser = userId
+}
+
+// AccessToken returns the OAuth access token.
+func (box *Box) AccessToken() string {
+	box.mu.RLock()
+	defer box.mu.RUnlock()
+	if box.token == nil {
+		return ""
+	}
+	return box.token.AccessToken
+}
+
+// Token returns the current token, refreshing it first if it expired,
+// which makes the box an oauth2.TokenSource. Persist the returned token
+// to resume later with SetToken. It is nil when no token is set.
+func (box *Box) Token() (*oauth2.Token, error) {
+	return box.tokenContext(context.Background())
+}
+
+// tokenContext is Token using ctx for the refresh request.
+func (box *Box) tokenContext(ctx context.Context) (*oauth2.Token, error) {
+	box.mu.RLock()
+	source := box.source
+	renew := box.renew
+	token := box.token
+	box.mu.RUnlock()
+
+	if source != nil {
+		t, err := source.Token()
+		if err != nil {
+			return nil, err
+		}
+		if t != token {
+			if err = box.saveToken(t); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	}
+	if token.Valid() || renew == nil && (token == nil || token.RefreshToken == "") {
+		return token, nil
+	}
+
+	// Box refresh tokens can only be used once, so only one goroutine
+	// refreshes while the others wait for its result.
+	box.refreshMu.Lock()
+	defer box.refreshMu.Unlock()
+
+	config := box.oauthConfig()
+	box.mu.RLock()
+	token = box.token
+	box.mu.RUnlock()
+	if token.Valid() {
+		return token, nil
+	}
+
+	var t *oauth2.Token
+	var err error
+	switch {
+	case renew != nil:
+		t, err = renew.renewToken(ctx)
+	case config == nil:
+		return nil, errors.New("Token expired and no app info to refresh it")
+	default:
+		t, err = config.TokenSource(box.oauthContext(ctx), token).Token()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = box.saveToken(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// currentToken returns the token the box currently uses, without
+// refreshing it.
+func (box *Box) currentToken() *oauth2.Token {
+	box.mu.RLock()
+	defer box.mu.RUnlock()
+	return box.token
+}
+
+// refreshToken refreshes the token after Box refused the token used,
+// even though it had not expired. Nothing is done if the token was
+// replaced in the meantime.
+func (box *Box) refreshToken(ctx context.Context, used *oauth2.Token) error {
+	box.refreshMu.Lock()
+	defer box.refreshMu.Unlock()
+
+	config := box.oauthConfig()
+	box.mu.RLock()
+	token := box.token
+	source := box.source
+	renew := box.renew
+	box.mu.RUnlock()
+	if token != used {
+		return nil
+	}
+
+	var t *oauth2.Token
+	var err error
+	switch {
+	case renew != nil:
+		if t, err = renew.renewToken(ctx); err != nil {
+			return err
+		}
+	case source != nil:
+		return errors.New("Token refused and its source cannot be refreshed")
+	case token == nil || token.RefreshToken == "" || config == nil:
+		return errors.New("Token refused and no refresh token or app info to refresh it")
+	default:
+		expired := &oauth2.Token{RefreshToken: token.RefreshToken}
+		if t, err = config.TokenSource(box.oauthContext(ctx), expired).Token(); err != nil {
+			return err
+		}
+	}
+	return box.saveToken(t)
+}
+
+// oauthConfig returns the app info of the box with its oauth2
+// endpoints, or nil when the app info is not set.
+func (box *Box) oauthConfig() *oauth2.Config {
+	box.mu.RLock()
+	config := box.config
+	box.mu.RUnlock()
+	if config == nil {
+		return nil
+	}
+	c := *config
+	c.Endpoint = box.endpoint()
+	return &c
+}
+
+// endpoint returns the oauth2 endpoint of the box, defaulting to the
+// one of Box.
+func (box *Box) endpoint() oauth2.Endpoint {
+	e := Endpoint
+	if box.AuthURL != "" {
+		e.AuthURL = box.AuthURL
+	}
+	if box.TokenURL != "" {
+		e.TokenURL = box.TokenURL
+	}
+	return e
+}
+
+// revokeURL returns the token revocation endpoint of the box,
+// defaulting to the one of Box.
+func (box *Box) revokeURL() string {
+	if box.RevokeURL != "" {
+		return box.RevokeURL
+	}
+	return RevokeURL
+}
+
+// oauthContext makes the oauth2 requests go through the http client
+// of the box, if one was given.
+func (box *Box) oauthContext(ctx context.Context) context.Context {
+	box.mu.RLock()
+	base := box.baseClient
+	box.mu.RUnlock()
+	if base == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, base)
+}
+
+// tokenClient returns the http client the token requests are sent
+// with. They must not go through the box client, which authorizes
+// requests with the token being requested.
+func (box *Box) tokenClient() *http.Client {
+	box.mu.RLock()
+	defer box.mu.RUnlock()
+	if box.baseClient != nil {
+		return box.baseClient
+	}
+	return http.DefaultClient
+}
+
+// postForm posts form to the oauth2 endpoint at rawurl and returns the
+// response body. Failures are returned as *oauth2.RetrieveError, like
+// the ones of the oauth2 flow.
+func (box *Box) postForm(ctx context.Context, rawurl string, form url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", rawurl, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := box.tokenClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		e := &oauth2.RetrieveError{Response: resp, Body: body}
+		var v struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &v) == nil {
+			e.ErrorCode = v.Error
+			e.ErrorDescription = v.Description
+		}
+		return nil, e
+	}
+	return body, nil
+}
+
+// requestToken posts form to the token endpoint of Box and returns the
+// token granted.
+func (box *Box) requestToken(ctx context.Context, form url.Values) (*oauth2.Token, error) {
+	body, err := box.postForm(ctx, box.endpoint().TokenURL, form)
+	if err != nil {
+		return nil, err
+	}
+	var v struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err = json.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	if v.AccessToken == "" {
+		return nil, errors.New("Empty access token in token response")
+	}
+	token := &oauth2.Token{
+		AccessToken:  v.AccessToken,
+		TokenType:    v.TokenType,
+		RefreshToken: v.RefreshToken,
+	}
+	if v.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(v.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// RevokeToken invalidates the access and refresh tokens of the box,
+// e.g. on logout, and clears them from the box and its token store.
+// The app info is required.
+func (box *Box) RevokeToken(ctx context.Context) error {
+	config := box.oauthConfig()
+	box.mu.RLock()
+	token := box.token
+	store := box.store
+	box.mu.RUnlock()
+	if config == nil {
+		return errors.New("Empty app info while using RevokeToken")
+	}
+	if token == nil {
+		return nil
+	}
+	_, err := box.postForm(ctx, box.revokeURL(), url.Values{
+		"client_id":     {config.ClientID},
+		"client_secret": {config.ClientSecret},
+		"token":         {token.AccessToken},
+	})
+	if err != nil {
+		return err
+	}
+	box.SetToken(nil)
+	if store != nil {
+		return store.Save(nil)
+	}
+	return nil
+}
+
+// DownscopeToken exchanges the token of the box for a short lived one
+// restricted to the given scopes, such as "item_preview", and to the
+// resource if not empty, e.g. "https://api.box.com/2.0/files/123". The
+// returned token can be handed to untrusted code such as Box UI
+// Elements; it cannot be refreshed.
+func (box *Box) DownscopeToken(ctx context.Context, scopes []string, resource string) (*oauth2.Token, error) {
+	if len(scopes) == 0 {
+		return nil, errors.New("Empty scopes while using DownscopeToken")
+	}
+	token, err := box.tokenContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errors.New("Empty token while using DownscopeToken")
+	}
+	form := url.Values{
+		"grant_type":         {"urn:ietf:params:oauth:grant-type:token-exchange"},
+		"subject_token":      {token.AccessToken},
+		"subject_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+		"scope":              {strings.Join(scopes, " ")},
+	}
+	if resource != "" {
+		form.Set("resource", resource)
+	}
+	return box.requestToken(ctx, form)
+}
+
+// Auth displays the URL to authorize this application to connect to your account.
+func (box *Box) Auth(ctx context.Context) error {
+	var code string
+	config := box.oauthConfig()
+	if config == nil {
+		return errors.New("Empty app info while using Auth")
+	}
+	fmt.Printf("Please visit:\n%s\nEnter the code: ",
+		config.AuthCodeURL(""))
+	fmt.Scanln(&code)
+	token, err := config.Exchange(box.oauthContext(ctx), code)
+	if err != nil {
+		return err
+	}
+	return box.saveToken(token)
+}
+
+// tokenTransport authorizes the requests going through the wrapped
+// transport with the current token of the box, refreshing it as
+// needed, on behalf of the impersonated user if any and through the
+// shared link of the request context if any. It also adds the version
+// and extra headers of the box and the headers of the options of the
+// call.
+type tokenTransport struct {
+	box  *Box
+	next http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.box.tokenContext(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, &AuthError{err}
+	}
+	t.box.mu.RLock()
+	asUser := t.box.asUser
+	t.box.mu.RUnlock()
+	r := req.Clone(req.Context())
+	if token != nil {
+		token.SetAuthHeader(r)
+	}
+	if asUser != "" {
+		r.Header.Set("As-User", asUser)
+	}
+	o := callOpts(r.Context())
+	if o != nil {
+		setCallHeaders(r, o)
+	}
+	t.box.setVersionHeaders(r)
+	t.box.setAgentHeaders(r)
+	resp, err := t.next.RoundTrip(r)
+	if err == nil && o != nil {
+		recordResponse(o, resp)
+	}
+	return resp, err
+}
@@ -0,0 +1,101 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// refreshAPI accepts the access token "fresh" only, which its token
+// endpoint hands out for any refresh token.
+type refreshAPI struct {
+	refreshes int32
+}
+
+func (a *refreshAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		atomic.AddInt32(&a.refreshes, 1)
+		// Let the other requests pile up behind the refresh.
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"access_token":"fresh","refresh_token":"next","token_type":"bearer","expires_in":3600}`)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer fresh" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	io.WriteString(w, `{"type":"file","id":"1"}`)
+}
+
+func TestConcurrentRefresh(t *testing.T) {
+	tests := []struct {
+		name  string
+		token *oauth2.Token
+	}{
+		{"expired", &oauth2.Token{AccessToken: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}},
+		{"revoked", &oauth2.Token{AccessToken: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &refreshAPI{}
+			b := newTestBox(t, api)
+			if err := b.SetAppInfo("id", "secret"); err != nil {
+				t.Fatal(err)
+			}
+			b.SetToken(tt.token)
+			var saved int32
+			b.OnTokenRefresh = func(*oauth2.Token) { atomic.AddInt32(&saved, 1) }
+
+			ctx := context.Background()
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, err := b.Files.Get(ctx, "1"); err != nil {
+						t.Error(err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			if n := atomic.LoadInt32(&api.refreshes); n != 1 {
+				t.Errorf("token refreshed %d times, want 1", n)
+			}
+			if n := atomic.LoadInt32(&saved); n != 1 {
+				t.Errorf("OnTokenRefresh called %d times, want 1", n)
+			}
+			if got := b.AccessToken(); got != "fresh" {
+				t.Errorf("access token %q, want fresh", got)
+			}
+		})
+	}
+}
+
+func TestCCGTokenContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	b, err := NewBoxCCG("client", "secret", "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestBox(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang the token request until the caller gives up, which the
+		// server only sees once the body is read.
+		r.ParseForm()
+		cancel()
+		<-r.Context().Done()
+	}))
+	b.APIURL, b.TokenURL = srv.APIURL, srv.TokenURL
+	_, err = b.CurrentUser(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("error %v, want the token request cancelled with the call", err)
+	}
+}
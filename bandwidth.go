@@ -0,0 +1,65 @@
+package box
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// BytesUploaded returns the number of request body bytes sent by the
+// box so far, uploads and JSON requests alike.
+func (box *Box) BytesUploaded() int64 {
+	return atomic.LoadInt64(&box.uploaded)
+}
+
+// BytesDownloaded returns the number of response body bytes received by
+// the box so far, downloads and JSON responses alike.
+func (box *Box) BytesDownloaded() int64 {
+	return atomic.LoadInt64(&box.downloaded)
+}
+
+// countBytes adds to the counters of the box and reports the amounts
+// to the bandwidth callback, if any.
+func (box *Box) countBytes(uploaded, downloaded int64) {
+	if uploaded == 0 && downloaded == 0 {
+		return
+	}
+	atomic.AddInt64(&box.uploaded, uploaded)
+	atomic.AddInt64(&box.downloaded, downloaded)
+	if box.OnBandwidth != nil {
+		box.OnBandwidth(uploaded, downloaded)
+	}
+}
+
+// countingTransport counts the body bytes going through the wrapped
+// transport.
+type countingTransport struct {
+	box  *Box
+	next http.RoundTripper
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		r := *req
+		r.Body = &countingBody{ReadCloser: req.Body, count: func(n int64) { t.box.countBytes(n, 0) }}
+		req = &r
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, count: func(n int64) { t.box.countBytes(0, n) }}
+	return resp, nil
+}
+
+// countingBody reports the bytes read through it.
+type countingBody struct {
+	io.ReadCloser
+	count func(n int64)
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.count(int64(n))
+	return n, err
+}
@@ -2,129 +2,770 @@ package box
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/golang/oauth2"
+	"golang.org/x/oauth2"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-// Box Client
+// Box Client. A single Box is safe for concurrent use by multiple
+// goroutines once it is configured: the exported fields should be set
+// before the first request and treated as read only afterwards, while
+// the state changed through methods, such as the token, the app info,
+// the http client or the impersonated user, is guarded internally and
+// may be changed at any time. Token refreshes are done by one
+// goroutine at a time, the others waiting for the new token. All
+// requests share one transport so connections are pooled across
+// goroutines.
 type Box struct {
+	// Kept first for 64-bit alignment of the atomic accesses.
+	uploaded   int64 // body bytes sent, accessed atomically
+	downloaded int64 // body bytes received, accessed atomically
+
+	// APIURL and APIUPLOADURL are the roots of the api and of uploads,
+	// and AuthURL, TokenURL and RevokeURL the oauth2 endpoints, see
+	// Endpoint and RevokeURL. Changing them points the box at another
+	// Box cloud or at a mock server.
 	APIURL       string
 	APIUPLOADURL string
-	config       *oauth2.Config
-	token        *oauth2.Token
+	AuthURL      string
+	TokenURL     string
+	RevokeURL    string
+
+	// Timeout limits the time of JSON API requests, and
+	// TransferTimeout the time of uploads and downloads which may
+	// legitimately take hours. Zero means no limit.
+	Timeout         time.Duration
+	TransferTimeout time.Duration
+
+	// OnBandwidth, if set, is called with the number of body bytes
+	// sent and received as they go over the wire, e.g. to attribute
+	// bandwidth costs. It may be called from several goroutines.
+	OnBandwidth func(uploaded, downloaded int64)
+
+	// MaxRetries is the number of times a request failing with a rate
+	// limit, or with a server error if it is idempotent, is retried.
+	// Retries wait for the Retry-After the server asks for, or else for
+	// RetryBackoff doubled at every attempt.
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	// AcceptedTimeout is how long an operation Box answers with 202
+	// Accepted, such as a large folder copy or a download not ready
+	// yet, is polled for completion, waiting for the Retry-After of
+	// every answer. Requests still accepted past it fail with ACCEPTED.
+	// Zero returns the accepted answer as is.
+	AcceptedTimeout time.Duration
+
+	// RequestsPerSecond, if set, limits the rate of the requests sent
+	// by the box, all goroutines together, so that concurrent workers
+	// slow down instead of running into rate limits. Up to Burst
+	// requests, at least one, may be sent at once after a pause.
+	RequestsPerSecond float64
+	Burst             int
+
+	// RetryUploadSize is the size up to which uploads are buffered in
+	// memory so that they can be retried like other requests. Larger
+	// uploads are streamed and never retried. Zero disables buffering.
+	RetryUploadSize int64
+
+	// ChunkedUploadSize is the size from which files of known size are
+	// uploaded part by part through an upload session instead of in a
+	// single request. Zero disables chunked uploads. Uploader, when
+	// set, uploads all the files instead, e.g. a fake in tests.
+	ChunkedUploadSize int64
+	Uploader          Uploader
+
+	// UploadSessions, when set, records the chunked uploads in progress
+	// so that uploading the same file to the same folder again, e.g.
+	// after a crash, only sends the parts Box does not have yet.
+	UploadSessions UploadSessionStore
+
+	// UploadWorkers is the number of parts of a chunked upload sent at
+	// once, each held in memory meanwhile. Parts are retried like other
+	// requests. Zero means 4.
+	UploadWorkers int
+
+	// ReadOnly makes the box refuse every request that could modify
+	// content with a ReadOnlyError, without sending it. Only GET, HEAD
+	// and OPTIONS requests are let through, so reads done with POST
+	// such as the AI calls are refused as well.
+	ReadOnly bool
+
+	// IdempotentDeletes makes the Delete and PurgeFromTrash methods
+	// succeed when the item is already gone, e.g. for cleanup jobs run
+	// again after a partial failure.
+	IdempotentDeletes bool
+
+	// MatchETags makes the Delete, Rename and Move methods of files and
+	// folders with an ETag send it as If-Match, so that they fail with
+	// PRECONDITION_FAILED instead of clobbering a concurrent change. Get
+	// sends it as If-None-Match and fails with a NotModifiedError,
+	// leaving the struct untouched, when the item did not change.
+	MatchETags bool
+
+	// CheckSharedLinkAccess makes the methods creating or changing a
+	// shared link first check that its access level is allowed on the
+	// item, failing with a SharedLinkAccessError instead of a 403 from
+	// Box. It costs a request unless the item was read with the
+	// allowed_shared_link_access_levels field.
+	CheckSharedLinkAccess bool
+
+	// Policy, if set, is asked before sending every api request and
+	// refuses it by returning an error, e.g. EndpointPolicy.Check. It
+	// gets the method and the path relative to the api root, such as
+	// "folders/0/items".
+	Policy func(method, path string) error
+
+	// SanitizeNames makes uploads and folder creation rewrite names
+	// Box would reject with SanitizeName instead of failing with a
+	// NameError.
+	SanitizeNames bool
+
+	// NormalizeNames makes uploads and folder creation convert names
+	// to the NFC unicode form, so that names written in NFD, as macOS
+	// does, do not end up as duplicate looking items. Names are then
+	// compared regardless of their normalization.
+	NormalizeNames bool
+
+	// APIVersion, if set, is sent as the Box-Version header to opt
+	// into a version of the api, except for the endpoints pinned to a
+	// version by the package. Header holds additional headers sent
+	// with every request, such as compatibility headers.
+	APIVersion string
+	Header     http.Header
+
+	// UserAgent, if set, is appended to the User-Agent and X-Box-UA
+	// headers identifying the package, e.g. "myapp/1.2", so that Box
+	// support can tell the traffic of the application apart.
+	UserAgent string
+
+	// ErrorBodySize, if set, is the number of bytes of the raw body of
+	// error responses kept in the Body of the returned BoxError, e.g.
+	// to see what Box answered to a bad upload.
+	ErrorBodySize int
+
+	// MaxBodySize limits the size of the api request and response
+	// bodies held in memory, failing with a BodyTooLargeError instead
+	// of exhausting it. Zero means no limit. Uploads and downloads are
+	// streamed and not affected.
+	MaxBodySize int64
+
+	// StrictDecoding makes decoding api responses fail on the fields
+	// the models do not capture. It is meant for tests detecting when
+	// Box adds fields, not for production use.
+	StrictDecoding bool
+
+	// Logger, if set, gets every request sent, retries included, with
+	// its method, url, status, duration and Box request id at debug
+	// level. LogBodies adds the headers, with the authorization
+	// redacted, and the bodies small enough not to be file content.
+	// The names of the response fields the models do not capture are
+	// logged at debug level too.
+	Logger    *slog.Logger
+	LogBodies bool
+
+	// Metrics, if set, gets the metrics of every request sent.
+	Metrics MetricsRecorder
+
+	// OnTokenRefresh, if set, is called with the new token whenever
+	// the box obtains one, by refreshing it or by Auth, e.g. to save
+	// it to a database. It is called after the TokenStore, if any, and
+	// may be called from several goroutines.
+	OnTokenRefresh func(token *oauth2.Token)
+
+	// Files, Folders, Users and Collaborations group the operations of
+	// the box by resource, taking ids rather than model structs, e.g.
+	// box.Files.Get(ctx, id). NewBox sets them to services sending the
+	// requests of the box, they may be replaced, e.g. by fakes in tests.
+	Files          FileService
+	Folders        FolderService
+	Users          UserService
+	Collaborations CollaborationService
+
+	config *oauth2.Config     // app info used for the auth flow and refreshes
+	token  *oauth2.Token      // current token
+	source oauth2.TokenSource // user supplied source of tokens, if any
+	renew  tokenRenewer       // source of new tokens, if the box renews them itself
+	store  TokenStore         // where the tokens obtained are saved, if anywhere
+	asUser string             // id of the user impersonated, if any
+
+	// userBox creates a box authenticated as a user with the same
+	// credentials, see ForUser.
+	userBox func(userId string) (*Box, error)
+
+	refreshMu      sync.Mutex   // serializes token refreshes
+	mu             sync.RWMutex // guards the auth fields, asUser, baseClient and the clients
+	baseClient     *http.Client // user supplied client the clients derive from
+	httpClient     *http.Client // client for JSON API requests
+	transferClient *http.Client // client for uploads and downloads
 }
 
-// NewBox gets the new Box object with appropriate APIURL.
+// NewBox gets the new Box object with appropriate APIURL and oauth2
+// endpoints.
 func NewBox() *Box {
 	box := &Box{
-		APIURL:       "https://api.box.com/2.0",
-		APIUPLOADURL: "https://upload.box.com/api/2.0",
+		APIURL:            "https://api.box.com/2.0",
+		APIUPLOADURL:      "https://upload.box.com/api/2.0",
+		AuthURL:           Endpoint.AuthURL,
+		TokenURL:          Endpoint.TokenURL,
+		RevokeURL:         RevokeURL,
+		MaxRetries:        3,
+		RetryBackoff:      time.Second,
+		RetryUploadSize:   8 << 20,
+		ChunkedUploadSize: defaultChunkedUploadSize,
 	}
+	box.Files = &fileService{box}
+	box.Folders = &folderService{box}
+	box.Users = &userService{box}
+	box.Collaborations = &collaborationService{box}
 	return box
 }
 
-// SetAppInfo adds oauth2 app info
-func (box *Box) SetAppInfo(clientid, clientsecret string) error {
-	var err error
-	box.config, err = oauth2.NewConfig(
-		&oauth2.Options{
-			ClientID:     clientid,
-			ClientSecret: clientsecret,
-		},
-		"https://app.box.com/api/oauth2/authorize",
-		"https://app.box.com/api/oauth2/token")
-	return err
+// NewBoxWithClient gets the new Box object which sends all its
+// requests, uploads and downloads included, through the given http
+// client. See SetHTTPClient.
+func NewBoxWithClient(client *http.Client) *Box {
+	box := NewBox()
+	box.SetHTTPClient(client)
+	return box
 }
 
-// SetAccessToken sets access token to avoid calling Auth method.
-func (box *Box) SetAccessToken(accesstoken string) {
-	box.token = &oauth2.Token{AccessToken: accesstoken}
+// SetHTTPClient makes the box send all its requests through the given
+// http client, e.g. to use a proxy or an instrumented transport. The
+// authorization header is added on top of the client transport. The
+// Timeout and TransferTimeout of the box, when set, take precedence
+// over the client timeout. A nil client restores the default.
+func (box *Box) SetHTTPClient(client *http.Client) {
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.baseClient = client
+	box.httpClient = nil
+	box.transferClient = nil
 }
 
-// AccessToken returns the OAuth access token.
-func (box *Box) AccessToken() string {
-	return box.token.AccessToken
+// Get the http client for further api accesses. The client is built
+// once and shared by all goroutines using the box.
+func (box *Box) client() *http.Client {
+	api, _ := box.clients()
+	return api
 }
 
-// Get the http client for further api accesses.
-func (box *Box) client() *http.Client {
-	var t *oauth2.Transport
-	t = box.config.NewTransport()
-	t.SetToken(box.token)
-	return &http.Client{Transport: t}
+// contentClient returns the http client for uploads and downloads. It
+// shares the transport of client but has its own timeout.
+func (box *Box) contentClient() *http.Client {
+	_, transfer := box.clients()
+	return transfer
 }
 
-// Auth displays the URL to authorize this application to connect to your account.
-func (box *Box) Auth() error {
-	var code string
-	var t *oauth2.Transport
-	var err error
-	fmt.Printf("Please visit:\n%s\nEnter the code: ",
-		box.config.AuthCodeURL(""))
-	fmt.Scanln(&code)
-	if t, err = box.config.NewTransportWithCode(code); err != nil {
-		return err
+// clients returns the shared clients, building them on first use.
+func (box *Box) clients() (api, transfer *http.Client) {
+	box.mu.RLock()
+	api, transfer = box.httpClient, box.transferClient
+	box.mu.RUnlock()
+	if api != nil {
+		return api, transfer
 	}
-	box.token = t.Token()
-	box.token.TokenType = "Bearer"
-	return nil
+	box.mu.Lock()
+	defer box.mu.Unlock()
+	box.initClients()
+	return box.httpClient, box.transferClient
+}
+
+// initClients builds the shared clients if not done yet. box.mu must
+// be held.
+func (box *Box) initClients() {
+	if box.httpClient != nil {
+		return
+	}
+	base := box.baseClient
+	if base == nil {
+		base = &http.Client{}
+	}
+	next := base.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	var t http.RoundTripper = &metricsTransport{box: box, next: next}
+	t = &logTransport{box: box, next: t}
+	t = &tokenTransport{box: box, next: t}
+	t = &countingTransport{box: box, next: t}
+	if box.RequestsPerSecond > 0 {
+		t = &limitTransport{limiter: newRateLimiter(box.RequestsPerSecond, box.Burst), next: t}
+	}
+	t = &guardTransport{box: box, next: t}
+	t = &deadlineTransport{next: t}
+
+	api := *base
+	api.Transport = t
+	if box.Timeout != 0 {
+		api.Timeout = box.Timeout
+	}
+	transfer := *base
+	transfer.Transport = t
+	if box.TransferTimeout != 0 {
+		transfer.Timeout = box.TransferTimeout
+	}
+	box.httpClient = &api
+	box.transferClient = &transfer
 }
 
 // doRequest performs the request (GET or POST) using authorized http
 // client. You can also pass params to encode them in the request url
-// or body to place in the request body.
-func (box *Box) doRequest(method, path string, params *url.Values, reqBody []byte) ([]byte, error) {
+// or body to place in the request body. The request is aborted when
+// ctx is done.
+func (box *Box) doRequest(ctx context.Context, method, path string, params *url.Values, reqBody []byte) ([]byte, error) {
+	return box.doRequestHeader(ctx, method, path, params, nil, reqBody)
+}
+
+// doRequestHeader is doRequest with additional headers set on the
+// request.
+func (box *Box) doRequestHeader(ctx context.Context, method, path string, params *url.Values, header http.Header, reqBody []byte) ([]byte, error) {
+	return box.doRequestAt(ctx, box.APIURL, method, path, params, header, reqBody)
+}
+
+// doRequestAt is doRequestHeader for a path relative to the given api
+// root, such as APIUPLOADURL.
+func (box *Box) doRequestAt(ctx context.Context, root, method, path string, params *url.Values, header http.Header, reqBody []byte) ([]byte, error) {
+	// If reqBody is empty then dont create new reader
+	if reqBody == nil {
+		return box.doRequestReader(ctx, root, method, path, params, header, nil, 0)
+	}
+	return box.doRequestReader(ctx, root, method, path, params, header, bytes.NewReader(reqBody), int64(len(reqBody)))
+}
+
+// doRequestReader is doRequestAt streaming the request body of the
+// given size from r instead of holding it in memory. The request is
+// only retried when r can be rewound, being an io.Seeker positioned at
+// the start of the body. r is not closed.
+func (box *Box) doRequestReader(ctx context.Context, root, method, path string, params *url.Values, header http.Header, r io.Reader, size int64) ([]byte, error) {
+	ctx, end := startSpan(ctx, method, path)
+	body, status, retries, err := box.sendRequest(ctx, root, method, path, params, header, r, size)
+	end(status, retries, err)
+	return body, err
+}
+
+// sendRequest sends the request of doRequestReader, retrying it as
+// needed. It also returns the status of the last response and the
+// number of retries.
+func (box *Box) sendRequest(ctx context.Context, root, method, path string, params *url.Values, header http.Header, reqBody io.Reader, size int64) ([]byte, int, int, error) {
 	var body []byte
 	var rawurl string
 	var response *http.Response
 	var request *http.Request
 	var err error
-	var reqBodyReader io.Reader
+	var status, attempt int
 
+	params = callParams(ctx, params)
 	// If paramerters are nil then dont add `?` to the url
 	if params == nil {
-		rawurl = fmt.Sprintf("%s/%s", box.APIURL, urlEncode(path))
+		rawurl = joinURL(root, path)
 	} else {
-		rawurl = fmt.Sprintf("%s/%s?%s", box.APIURL, urlEncode(path), params.Encode())
+		rawurl = fmt.Sprintf("%s?%s", joinURL(root, path), params.Encode())
 	}
 
-	// If reqBody is empty then dont create new reader
-	if reqBody != nil {
-		reqBodyReader = bytes.NewReader([]byte(reqBody))
+	if box.MaxBodySize > 0 && size > box.MaxBodySize {
+		return nil, 0, 0, &BodyTooLargeError{box.MaxBodySize}
 	}
 
-	if request, err = http.NewRequest(method, rawurl, reqBodyReader); err != nil {
-		return nil, err
+	// The body can only be sent again if it can be rewound.
+	var start int64
+	seeker, replayable := reqBody.(io.Seeker)
+	if replayable {
+		if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			return nil, 0, 0, err
+		}
 	}
-	if response, err = box.client().Do(request); err != nil {
-		return nil, err
+	replayable = replayable || reqBody == nil
+
+	reauthed := false
+	var deadline time.Time
+	for ; ; attempt++ {
+		used := box.currentToken()
+
+		if seeker != nil {
+			if _, err = seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, status, attempt, err
+			}
+		}
+		if request, err = newRequest(ctx, method, rawurl, reqBody, size); err != nil {
+			return nil, status, attempt, err
+		}
+		for k, v := range header {
+			request.Header[k] = append([]string(nil), v...)
+		}
+		if response, err = box.client().Do(request); err != nil {
+			return nil, status, attempt, err
+		}
+		body, err = box.getResponse(response)
+		closeResponse(response)
+		status = response.StatusCode
+
+		// Refresh a refused token once and replay the request if
+		// doing so twice is harmless.
+		if response.StatusCode == UNAUTHORIZED.StatusCode && !reauthed && idempotent(method) && replayable {
+			reauthed = true
+			if rerr := box.refreshToken(ctx, used); rerr != nil {
+				return nil, status, attempt, &AuthError{rerr}
+			}
+			continue
+		}
+		// Poll the operation Box accepted, at the url it gives if any,
+		// else by sending the request again if that is harmless.
+		if response.StatusCode == ACCEPTED.StatusCode && box.AcceptedTimeout > 0 {
+			location := response.Header.Get("Location")
+			if location != "" || idempotent(method) && replayable {
+				if deadline.IsZero() {
+					deadline = time.Now().Add(box.AcceptedTimeout)
+				}
+				if time.Now().After(deadline) {
+					return nil, status, attempt, ACCEPTED
+				}
+				if location != "" {
+					u, uerr := request.URL.Parse(location)
+					if uerr != nil {
+						return nil, status, attempt, uerr
+					}
+					method, rawurl, reqBody, size = "GET", u.String(), nil, 0
+					seeker, replayable = nil, true
+				}
+				if err = sleep(ctx, box.pollDelay(response.Header.Get("Retry-After"))); err != nil {
+					return nil, status, attempt, err
+				}
+				attempt-- // polls are not retries
+				continue
+			}
+		}
+		if !retryable(method, response.StatusCode) || attempt >= box.MaxRetries || !replayable {
+			break
+		}
+		if err = sleep(ctx, box.backoff(attempt, response.Header.Get("Retry-After"))); err != nil {
+			return nil, status, attempt, err
+		}
+	}
+	if err != nil {
+		return nil, status, attempt, err
 	}
-	defer response.Body.Close()
-	if body, err = getResponse(response); err != nil {
+	return body, status, attempt, nil
+}
+
+// newRequest returns a request sending size bytes of body.
+func newRequest(ctx context.Context, method, rawurl string, body io.Reader, size int64) (*http.Request, error) {
+	if body == nil {
+		return http.NewRequestWithContext(ctx, method, rawurl, nil)
+	}
+	if b, ok := body.(*bytes.Reader); ok {
+		// Let http handle the length and replays of in memory bodies.
+		return http.NewRequestWithContext(ctx, method, rawurl, b)
+	}
+	// Hide Close so that the client does not close the caller's body.
+	request, err := http.NewRequestWithContext(ctx, method, rawurl, ioutil.NopCloser(body))
+	if err != nil {
 		return nil, err
 	}
-	return body, nil
+	request.ContentLength = size
+	return request, nil
+}
+
+// fieldsParams returns the query parameters requesting the given
+// fields of an object, or nil to get its default fields.
+func fieldsParams(fields []string) *url.Values {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &url.Values{"fields": {strings.Join(fields, ",")}}
+}
+
+// markerPage is a single page of a marker based listing, its entries
+// left raw. It has the paging fields of ListResult so that it decodes
+// strictly.
+type markerPage struct {
+	Entries    json.RawMessage `json:"entries,omitempty"`
+	TotalCount int64           `json:"total_count,omitempty"`
+	Limit      int64           `json:"limit,omitempty"`
+	Offset     int64           `json:"offset,omitempty"`
+	NextMarker string          `json:"next_marker,omitempty"`
+	PrevMarker string          `json:"prev_marker,omitempty"`
+	Order      []ListOrder     `json:"order,omitempty"`
+}
+
+// listAll follows the markers of the listing at path until the last
+// page, calling fn with the raw entries of every page.
+func (box *Box) listAll(ctx context.Context, path string, params url.Values, header http.Header, fn func(entries json.RawMessage) error) error {
+	return box.listPages(ctx, path, params, header, "", func(entries json.RawMessage, next string) error {
+		return fn(entries)
+	})
+}
+
+// listPages is listAll starting at the given marker, also passing the
+// marker of the next page to fn. The marker is empty on the last page.
+func (box *Box) listPages(ctx context.Context, path string, params url.Values, header http.Header, marker string, fn func(entries json.RawMessage, next string) error) error {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", strconv.Itoa(callLimit(ctx, 1000)))
+	if marker != "" {
+		params.Set("marker", marker)
+	}
+	for {
+		body, err := box.doRequestHeader(ctx, "GET", path, &params, header, nil)
+		if err != nil {
+			return err
+		}
+		var page markerPage
+		if err = box.decode(body, &page); err != nil {
+			return err
+		}
+		if err = fn(page.Entries, page.NextMarker); err != nil {
+			return err
+		}
+		if page.NextMarker == "" {
+			return nil
+		}
+		params.Set("marker", page.NextMarker)
+	}
+}
+
+// deleted returns the error of a delete, ignoring NOT_FOUND when the
+// box has IdempotentDeletes.
+func (box *Box) deleted(err error) error {
+	if box.IdempotentDeletes && errors.Is(err, NOT_FOUND) {
+		return nil
+	}
+	return err
+}
+
+// ifMatch returns the If-Match header of a change to an item with the
+// given etag, or nil unless the box has MatchETags.
+func (box *Box) ifMatch(etag string) http.Header {
+	if !box.MatchETags || etag == "" {
+		return nil
+	}
+	return http.Header{"If-Match": {etag}}
+}
+
+// ifNoneMatch returns the If-None-Match header of a get of an item with
+// the given etag, or nil unless the box has MatchETags.
+func (box *Box) ifNoneMatch(etag string) http.Header {
+	if !box.MatchETags || etag == "" {
+		return nil
+	}
+	return http.Header{"If-None-Match": {etag}}
+}
+
+// changedSince gets the item at path unless its etag is still etag,
+// reporting whether it changed. Only the etag of the item is fetched
+// and stored into v.
+func (box *Box) changedSince(ctx context.Context, path, etag string, v interface{}) (bool, error) {
+	params := &url.Values{"fields": {"type,id,etag"}}
+	header := http.Header{"If-None-Match": {etag}}
+	body, err := box.doRequestHeader(ctx, "GET", path, params, header, nil)
+	if errors.Is(err, NOT_MODIFIED) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, box.decode(body, v)
 }
 
-func getResponse(r *http.Response) ([]byte, error) {
+// decode unmarshals the api response body into v. The names of the
+// fields v does not capture are logged to the Logger of the box at
+// debug level, and make decoding fail when the box decodes strictly.
+func (box *Box) decode(body []byte, v interface{}) error {
+	logger := box.Logger
+	logging := logger != nil && logger.Enabled(context.Background(), slog.LevelDebug)
+	if !box.StrictDecoding && !logging {
+		return json.Unmarshal(body, v)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+	var names []string
+	unknownFields(body, reflect.TypeOf(v), "", &names)
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	if logging {
+		logger.Debug("box response has unknown fields", "type", fmt.Sprintf("%T", v), "fields", names)
+	}
+	if box.StrictDecoding {
+		return fmt.Errorf("Strict decoding of %T: unknown fields %s", v, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// unmarshalerType is the type of the values decoding themselves, whose
+// fields are not checked.
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields adds to names the fields of the JSON data that a value
+// of type t does not capture, prefixed with the path leading to them.
+// Like json.Decoder.DisallowUnknownFields, it does not look into
+// interfaces, maps of them or values decoding themselves.
+func unknownFields(data []byte, t reflect.Type, prefix string, names *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		var m map[string]json.RawMessage
+		if json.Unmarshal(data, &m) != nil {
+			return
+		}
+		fields := jsonFields(t)
+		for k, v := range m {
+			ft, ok := fields[strings.ToLower(k)]
+			if !ok {
+				if !contains(*names, prefix+k) {
+					*names = append(*names, prefix+k)
+				}
+				continue
+			}
+			unknownFields(v, ft, prefix+k+".", names)
+		}
+	case reflect.Slice, reflect.Array:
+		var a []json.RawMessage
+		if json.Unmarshal(data, &a) != nil {
+			return
+		}
+		for _, v := range a {
+			unknownFields(v, t.Elem(), prefix, names)
+		}
+	case reflect.Map:
+		var m map[string]json.RawMessage
+		if json.Unmarshal(data, &m) != nil {
+			return
+		}
+		for _, v := range m {
+			unknownFields(v, t.Elem(), prefix, names)
+		}
+	}
+}
+
+// jsonFields returns the types of the fields of the struct type t by
+// their lower cased JSON names, the ones of embedded structs included
+// unless shadowed, as encoding/json matches them.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+	for _, et := range embedded {
+		for name, ft := range jsonFields(et) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = ft
+			}
+		}
+	}
+	return fields
+}
+
+// getResponse reads the body of the response, failing if it is larger
+// than the MaxBodySize of the box. Success statuses are not errors,
+// error statuses are returned as BoxError or one of its typed
+// counterparts.
+func (box *Box) getResponse(r *http.Response) ([]byte, error) {
 	var b []byte
 	var err error
-	if b, err = ioutil.ReadAll(r.Body); err != nil {
+	if b, err = readBody(r.Body, box.MaxBodySize); err != nil {
 		return nil, err
 	}
-	err = toError(r.StatusCode)
-	if err == SUCCESS {
+	switch {
+	case r.StatusCode >= 200 && r.StatusCode < 300:
 		return b, nil
+	case r.StatusCode >= 400:
+		err = parseError(r.StatusCode, r.Header, b)
+		var e *BoxError
+		if box.ErrorBodySize > 0 && errors.As(err, &e) {
+			e.Body = b
+			if len(b) > box.ErrorBodySize {
+				e.Body = b[:box.ErrorBodySize]
+			}
+		}
+		return b, err // still returns b
+	}
+	if r.StatusCode == NOT_MODIFIED.StatusCode {
+		return b, &NotModifiedError{toError(r.StatusCode)}
 	}
-	return b, err // still returns b
+	return b, toError(r.StatusCode) // still returns b
 }
 
-// urlEncode encodes s for url
+// readBody reads all of r, failing if it is larger than limit bytes
+// unless limit is zero.
+func readBody(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return ioutil.ReadAll(r)
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > limit {
+		return nil, &BodyTooLargeError{limit}
+	}
+	return b, nil
+}
+
+// closeResponse drains whatever is left of the response body before
+// closing it so that the underlying connection goes back to the pool
+// even when the body was not (fully) read.
+func closeResponse(r *http.Response) {
+	io.Copy(ioutil.Discard, r.Body)
+	r.Body.Close()
+}
+
+// joinURL returns the url of the path relative to the api root, which
+// may or may not end with a slash.
+func joinURL(root, path string) string {
+	return strings.TrimSuffix(root, "/") + "/" + urlEncode(path)
+}
+
+// urlEncode encodes the segments of the path s for url, keeping the
+// slashes between them.
 func urlEncode(s string) string {
-	encoded := url.QueryEscape(s)
-	return encoded
+	segments := strings.Split(s, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
 }
@@ -0,0 +1,282 @@
+package box
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestBox returns a box sending all its requests, token ones
+// included, to a test server serving h.
+func newTestBox(t *testing.T, h http.Handler) *Box {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	b := NewBox()
+	b.APIURL = srv.URL
+	b.APIUPLOADURL = srv.URL
+	b.TokenURL = srv.URL + "/token"
+	b.RetryBackoff = time.Millisecond
+	b.SetAccessToken("token")
+	return b
+}
+
+// fakeAPI serves the file contents, uploads and folder listings the
+// concurrency tests need.
+type fakeAPI struct {
+	mu    sync.Mutex
+	files map[string][]byte
+	next  int
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{files: make(map[string][]byte)}
+}
+
+func (a *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/files/content":
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		part, header, err := r.FormFile("filename")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		content, _ := ioutil.ReadAll(part)
+		a.mu.Lock()
+		a.next++
+		id := strconv.Itoa(a.next)
+		a.files[id] = content
+		a.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"total_count":1,"entries":[{"type":"file","id":%q,"name":%q,"size":%d}]}`, id, header.Filename, len(content))
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/content"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/files/"), "/content")
+		a.mu.Lock()
+		content, ok := a.files[id]
+		a.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, id, time.Time{}, bytes.NewReader(content))
+	case r.Method == "GET" && r.URL.Path == "/folders/0/items":
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		if offset >= 3 {
+			fmt.Fprint(w, `{"total_count":3,"entries":[]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"total_count":3,"offset":%d,"entries":[{"type":"folder","id":"%d"}]}`, offset, offset+10)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	b := newTestBox(t, newFakeAPI())
+	ctx := context.Background()
+
+	const workers = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- func() error {
+				content := fmt.Sprintf("content of worker %d", i)
+				f, err := b.Files.Upload(ctx, "0", fmt.Sprintf("file%d.txt", i), strings.NewReader(content))
+				if err != nil {
+					return fmt.Errorf("upload: %v", err)
+				}
+				var buf bytes.Buffer
+				if err := b.Files.Download(ctx, f.Id, &buf); err != nil {
+					return fmt.Errorf("download: %v", err)
+				}
+				if buf.String() != content {
+					return fmt.Errorf("downloaded %q, want %q", buf.String(), content)
+				}
+				it := b.Folders.Items(ctx, "0", &ListOptions{Limit: 1})
+				n := 0
+				for it.Next() {
+					n++
+				}
+				if err := it.Err(); err != nil {
+					return fmt.Errorf("listing: %v", err)
+				}
+				if n != 3 {
+					return fmt.Errorf("listed %d items, want 3", n)
+				}
+				return nil
+			}()
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestConcurrentConfigChanges(t *testing.T) {
+	b := newTestBox(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"type":"file","id":"1"}`)
+	}))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := b.Files.Get(ctx, "1"); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				b.SetAccessToken(fmt.Sprintf("token%d", i))
+				b.SetAsUser(strconv.Itoa(j))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// trackingTransport counts the response bodies not closed yet.
+type trackingTransport struct {
+	next http.RoundTripper
+	open int32
+}
+
+func (t *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddInt32(&t.open, 1)
+	resp.Body = &trackedBody{ReadCloser: resp.Body, t: t}
+	return resp, nil
+}
+
+type trackedBody struct {
+	io.ReadCloser
+	t    *trackingTransport
+	once sync.Once
+}
+
+func (b *trackedBody) Close() error {
+	b.once.Do(func() { atomic.AddInt32(&b.t.open, -1) })
+	return b.ReadCloser.Close()
+}
+
+// failingWriter fails every write, like a full disk.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestNoLeaks(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	api := newFakeAPI()
+	// Large enough for the transport not to drain what is left itself.
+	api.files["big"] = bytes.Repeat([]byte("x"), 8<<20)
+	var conns int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/broken":
+			http.Error(w, strings.Repeat("e", 64<<10), http.StatusInternalServerError)
+		case "/conflict/files/content":
+			w.WriteHeader(http.StatusConflict)
+			io.WriteString(w, `{"type":"error","status":409,"code":"item_name_in_use"}`)
+		default:
+			api.ServeHTTP(w, r)
+		}
+	}))
+	srv.Config.ConnState = func(c net.Conn, s http.ConnState) {
+		if s == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	srv.Start()
+
+	transport := &trackingTransport{next: &http.Transport{}}
+	b := NewBoxWithClient(&http.Client{Transport: transport})
+	b.APIURL = srv.URL
+	b.APIUPLOADURL = srv.URL
+	b.MaxRetries = 1
+	b.RetryBackoff = time.Millisecond
+	b.SetAccessToken("token")
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr bool
+	}{
+		{"download", func() error { return b.Files.Download(ctx, "big", ioutil.Discard) }, false},
+		{"download not found", func() error { return b.Files.Download(ctx, "missing", ioutil.Discard) }, true},
+		{"download write error", func() error { return b.Files.Download(ctx, "big", failingWriter{}) }, true},
+		{"upload", func() error {
+			_, err := b.Files.Upload(ctx, "0", "a.txt", strings.NewReader("content"))
+			return err
+		}, false},
+		{"upload conflict", func() error {
+			b.APIUPLOADURL = srv.URL + "/conflict"
+			defer func() { b.APIUPLOADURL = srv.URL }()
+			_, err := b.Files.Upload(ctx, "0", "a.txt", strings.NewReader("content"))
+			return err
+		}, true},
+		{"server error", func() error {
+			_, err := b.Files.Get(ctx, "broken")
+			return err
+		}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		if n := atomic.LoadInt32(&transport.open); n != 0 {
+			t.Errorf("%s: %d response bodies left open", tt.name, n)
+		}
+	}
+	// The calls are sequential, so they all reuse the first connection
+	// unless a body was left unread.
+	if n := atomic.LoadInt32(&conns); n != 1 {
+		t.Errorf("%d connections opened, want 1", n)
+	}
+
+	srv.Close()
+	transport.next.(*http.Transport).CloseIdleConnections()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			t.Fatalf("%d goroutines left, want %d:\n%s", runtime.NumGoroutine(), baseline, buf[:runtime.Stack(buf, true)])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,213 @@
+package box
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// ItemCache caches files and folders by id, and the items found at
+// paths, e.g. for a daemon resolving the same paths over and over. It
+// is an EventSink: watching a folder with it drops the entries made
+// stale by the renames, moves, trashes and uploads of the items under
+// that folder, so no expiry has to be guessed.
+//
+//	cache := b.NewItemCache()
+//	stop := watcher.WatchSink("0", cache)
+//	defer stop()
+//	item, err := cache.Lookup(ctx, "Projects/2024/report.pdf")
+//
+// It is safe for concurrent use.
+type ItemCache struct {
+	box     *Box
+	mu      sync.Mutex
+	files   map[string]*File
+	folders map[string]*Folder
+	paths   map[string]*cachedPath
+	gen     uint64 // bumped by every invalidation
+}
+
+// cachedPath is the item found at a path, with the ids of the folders
+// leading to it.
+type cachedPath struct {
+	item Entity
+	ids  []string
+}
+
+// NewItemCache returns an empty cache of the items of the box.
+func (box *Box) NewItemCache() *ItemCache {
+	return &ItemCache{
+		box:     box,
+		files:   make(map[string]*File),
+		folders: make(map[string]*Folder),
+		paths:   make(map[string]*cachedPath),
+	}
+}
+
+// File returns the file with the given id, getting it through
+// box.Files unless it is cached. The file returned must not be
+// changed.
+func (c *ItemCache) File(ctx context.Context, id string) (*File, error) {
+	c.mu.Lock()
+	f, gen := c.files[id], c.gen
+	c.mu.Unlock()
+	if f != nil {
+		return f, nil
+	}
+	f, err := c.box.Files.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if gen == c.gen {
+		c.files[id] = f
+	}
+	c.mu.Unlock()
+	return f, nil
+}
+
+// Folder returns the folder with the given id, getting it through
+// box.Folders unless it is cached. The folder returned must not be
+// changed.
+func (c *ItemCache) Folder(ctx context.Context, id string) (*Folder, error) {
+	c.mu.Lock()
+	f, gen := c.folders[id], c.gen
+	c.mu.Unlock()
+	if f != nil {
+		return f, nil
+	}
+	f, err := c.box.Folders.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if gen == c.gen {
+		c.folders[id] = f
+	}
+	c.mu.Unlock()
+	return f, nil
+}
+
+// Lookup returns the item at the given path, relative to the root
+// folder and separated by slashes, listing the folders along it unless
+// it is cached. It fails with a NotFoundError when there is no such
+// item. The empty path is the root folder.
+func (c *ItemCache) Lookup(ctx context.Context, path string) (*Entity, error) {
+	p, err := c.lookup(ctx, strings.Trim(path, "/"))
+	if err != nil {
+		return nil, err
+	}
+	item := p.item
+	return &item, nil
+}
+
+func (c *ItemCache) lookup(ctx context.Context, path string) (*cachedPath, error) {
+	if path == "" {
+		return &cachedPath{item: Entity{Id: "0", Type: "folder"}}, nil
+	}
+	c.mu.Lock()
+	p, gen := c.paths[path], c.gen
+	c.mu.Unlock()
+	if p != nil {
+		return p, nil
+	}
+
+	dir, name := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		dir, name = path[:i], path[i+1:]
+	}
+	parent, err := c.lookup(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.item.IsFolder() {
+		return nil, &NotFoundError{&BoxError{StatusCode: 404, Message: "Not a folder at path " + dir}}
+	}
+	opts := &ListOptions{UseMarker: true, Fields: []string{"type", "id", "etag", "name"}}
+	it := c.box.Folders.Items(ctx, parent.item.Id, opts)
+	for it.Next() {
+		if item := it.Item(); c.box.sameName(item.Name, name) {
+			ids := append(append([]string(nil), parent.ids...), parent.item.Id)
+			p = &cachedPath{item: item, ids: ids}
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, &NotFoundError{&BoxError{StatusCode: 404, Message: "No item at path " + path}}
+	}
+	c.mu.Lock()
+	if gen == c.gen {
+		c.paths[path] = p
+	}
+	c.mu.Unlock()
+	return p, nil
+}
+
+// Invalidate drops the item with the given id from the cache, along
+// with the paths going through it and the folders listing it.
+func (c *ItemCache) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	delete(c.files, id)
+	delete(c.folders, id)
+	for path, p := range c.paths {
+		if p.item.Id == id || contains(p.ids, id) {
+			delete(c.paths, path)
+		}
+	}
+	for fid, f := range c.folders {
+		if f.ItemCollection == nil {
+			continue
+		}
+		for i := range f.ItemCollection.Entry {
+			if f.ItemCollection.Entry[i].Id == id {
+				delete(c.folders, fid)
+				break
+			}
+		}
+	}
+}
+
+// invalidateListing drops the cached folder with the given id, whose
+// items changed, keeping the paths through it.
+func (c *ItemCache) invalidateListing(id string) {
+	c.mu.Lock()
+	c.gen++
+	delete(c.folders, id)
+	c.mu.Unlock()
+}
+
+// Publish invalidates the entries the event made stale. It never
+// fails.
+func (c *ItemCache) Publish(ctx context.Context, e *Event) error {
+	src := e.Item()
+	if src == nil {
+		return nil
+	}
+	switch e.EventType {
+	case "ITEM_RENAME", "ITEM_MOVE", "ITEM_TRASH", "ITEM_UNDELETE_VIA_TRASH", "ITEM_UPLOAD":
+		c.Invalidate(src.Id)
+	case "ITEM_CREATE", "ITEM_COPY":
+	default:
+		return nil
+	}
+	// The new parent lists one more item.
+	if src.Parent != nil {
+		c.invalidateListing(src.Parent.Id)
+	}
+	return nil
+}
+
+// contains checks if ids has id.
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/oauth2"
+	"net/url"
+)
+
+// NewBoxCCG gets a new Box object authenticated as the service account
+// of the given enterprise with the client credentials grant. Tokens
+// are requested with the client id and secret of the app and renewed
+// automatically.
+func NewBoxCCG(clientid, clientsecret, enterpriseId string) (*Box, error) {
+	if enterpriseId == "" {
+		return nil, errors.New("Empty enterprise id while using NewBoxCCG")
+	}
+	return newBoxCCG(clientid, clientsecret, "enterprise", enterpriseId)
+}
+
+// NewBoxCCGUser is NewBoxCCG authenticating as the given user instead
+// of the service account.
+func NewBoxCCGUser(clientid, clientsecret, userId string) (*Box, error) {
+	if userId == "" {
+		return nil, errors.New("Empty user id while using NewBoxCCGUser")
+	}
+	return newBoxCCG(clientid, clientsecret, "user", userId)
+}
+
+func newBoxCCG(clientid, clientsecret, subType, subId string) (*Box, error) {
+	if clientid == "" || clientsecret == "" {
+		return nil, errors.New("Empty client id or secret while using NewBoxCCG")
+	}
+	box := NewBox()
+	box.setRenewingSource(&ccgSource{
+		box: box,
+		form: url.Values{
+			"grant_type":       {"client_credentials"},
+			"client_id":        {clientid},
+			"client_secret":    {clientsecret},
+			"box_subject_type": {subType},
+			"box_subject_id":   {subId},
+		},
+	})
+	box.userBox = func(userId string) (*Box, error) {
+		return newBoxCCG(clientid, clientsecret, "user", userId)
+	}
+	return box, nil
+}
+
+// ccgSource gets tokens with the client credentials grant.
+type ccgSource struct {
+	box  *Box
+	form url.Values
+}
+
+// renewToken requests a token with the client credentials, bound to
+// the context of the call needing it.
+func (s *ccgSource) renewToken(ctx context.Context) (*oauth2.Token, error) {
+	return s.box.requestToken(ctx, s.form)
+}
@@ -0,0 +1,281 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Collaboration struct {
+	Id           string   `json:"id,omitempty"`             // The id of the collaboration.
+	Type         string   `json:"type,omitempty"`           // Type of the object, always collaboration.
+	Item         *Entity  `json:"item,omitempty"`           // The folder or file collaborated on.
+	AccessibleBy *Entity  `json:"accessible_by,omitempty"`  // The user or group given access.
+	Role         string   `json:"role,omitempty"`           // The level of access, e.g. editor, viewer or co-owner.
+	Status       string   `json:"status,omitempty"`         // Whether the collaboration is accepted, pending or rejected.
+	InviteEmail  string   `json:"invite_email,omitempty"`   // The email invited when the collaborator has no account.
+	CreatedBy    *Entity  `json:"created_by,omitempty"`     // The user who created the collaboration.
+	CreatedAt    *BoxTime `json:"created_at,omitempty"`     // When the collaboration was created.
+	ModifiedAt   *BoxTime `json:"modified_at,omitempty"`    // When the collaboration was last updated.
+	ExpiresAt    *BoxTime `json:"expires_at,omitempty"`     // When the collaboration is removed by itself.
+	AccessOnly   bool     `json:"is_access_only,omitempty"` // Whether the collaborator can access the folder without seeing it in their tree.
+	CanViewPath  bool     `json:"can_view_path,omitempty"`  // Whether the collaborator can see the parent folders of the item.
+
+	AcceptanceRequirements *AcceptanceRequirements `json:"acceptance_requirements_status,omitempty"` // What the collaborator must satisfy to accept, if requested.
+}
+
+// AcceptanceRequirements tells what a collaborator must satisfy before
+// accepting a collaboration, as set by the enterprise.
+type AcceptanceRequirements struct {
+	TermsOfService *struct {
+		IsAccepted     bool    `json:"is_accepted,omitempty"`      // Whether the collaborator accepted the terms of service.
+		TermsOfService *Entity `json:"terms_of_service,omitempty"` // The terms of service to accept.
+	} `json:"terms_of_service_requirement,omitempty"`
+	StrongPassword *struct {
+		Required    bool `json:"enterprise_has_strong_password_required_for_external_users,omitempty"` // Whether external collaborators need a strong password.
+		HasPassword bool `json:"user_has_strong_password,omitempty"`                                   // Whether the collaborator has one.
+	} `json:"strong_password_requirement,omitempty"`
+	TwoFactorAuth *struct {
+		Required   bool `json:"enterprise_has_two_factor_auth_enabled,omitempty"`     // Whether the enterprise requires two factor authentication.
+		HasEnabled bool `json:"user_has_two_factor_authentication_enabled,omitempty"` // Whether the collaborator enabled it.
+	} `json:"two_factor_authentication_requirement,omitempty"`
+}
+
+// CollaborationOptions holds the optional settings of a collaboration.
+type CollaborationOptions struct {
+	ExpiresAt   time.Time // When the collaboration is removed by itself, never if zero.
+	AccessOnly  bool      // Whether the folder stays out of the tree of the collaborator.
+	CanViewPath bool      // Whether the collaborator can see the parent folders, only for editors and above.
+}
+
+// apply sets the options on the collaboration.
+func (o *CollaborationOptions) apply(c *Collaboration) {
+	if o == nil {
+		return
+	}
+	if !o.ExpiresAt.IsZero() {
+		expires := BoxTime(o.ExpiresAt)
+		c.ExpiresAt = &expires
+	}
+	c.AccessOnly = o.AccessOnly
+	c.CanViewPath = o.CanViewPath
+}
+
+// Get populates the fields of the collaboration, only the ones of
+// WithFields if given, such as "acceptance_requirements_status". Note
+// that only Id is required apriori.
+//
+// Deprecated: Use box.Collaborations.Get.
+func (c *Collaboration) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	return (&collaborationService{box}).get(ctx, c, options)
+}
+
+// get populates c, see Collaboration.Get.
+func (s *collaborationService) get(ctx context.Context, c *Collaboration, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if c.Id == "" {
+		return errors.New("Empty id while using Get")
+	}
+	rawurl := fmt.Sprintf("collaborations/%s", c.Id)
+	body, err := box.doRequest(ctx, "GET", rawurl, nil, nil)
+	if err != nil {
+		return err
+	}
+	return box.decode(body, c)
+}
+
+// Collaborations returns the collaborations of the folder. Note that
+// only Id is required apriori.
+//
+// Deprecated: Use box.Folders.Collaborations.
+func (f *Folder) Collaborations(ctx context.Context, box *Box, options ...RequestOption) ([]Collaboration, error) {
+	return box.Folders.Collaborations(ctx, f.Id, options...)
+}
+
+// collaborations lists the collaborations of f, see
+// Folder.Collaborations.
+func (s *folderService) collaborations(ctx context.Context, f *Folder, options []RequestOption) ([]Collaboration, error) {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using Collaborations")
+	}
+	var collabs []Collaboration
+	it := f.CollaborationsIterator(ctx, box, &ListOptions{Limit: callLimit(ctx, 1000)}, options...)
+	for it.Next() {
+		collabs = append(collabs, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return collabs, nil
+}
+
+// CollaborationsPage returns a single page of the collaborations of
+// the folder with its paging information. Folder collaborations are
+// paged with markers only. Note that only Id is required apriori.
+func (f *Folder) CollaborationsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[Collaboration], error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using CollaborationsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	rawurl := fmt.Sprintf("folders/%s/collaborations", f.Id)
+	return listPage[Collaboration](ctx, box, rawurl, opts.markerValues(), nil)
+}
+
+// Collaborate gives the user or group, with Id and Type set, access to
+// the folder with the given role. Note that only Id of the folder is
+// required apriori.
+//
+// Deprecated: Use box.Collaborations.Create.
+func (f *Folder) Collaborate(ctx context.Context, box *Box, accessibleBy *Entity, role string, options ...RequestOption) (*Collaboration, error) {
+	return (&collaborationService{box}).create(ctx, f, accessibleBy, role, nil, options)
+}
+
+// CollaborateWith is Collaborate with the given options, if any.
+func (f *Folder) CollaborateWith(ctx context.Context, box *Box, accessibleBy *Entity, role string, opts *CollaborationOptions, options ...RequestOption) (*Collaboration, error) {
+	return (&collaborationService{box}).create(ctx, f, accessibleBy, role, opts, options)
+}
+
+// create collaborates on f, see Folder.CollaborateWith.
+func (s *collaborationService) create(ctx context.Context, f *Folder, accessibleBy *Entity, role string, opts *CollaborationOptions, options []RequestOption) (*Collaboration, error) {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using Collaborate")
+	}
+	if accessibleBy == nil || accessibleBy.Id == "" || role == "" {
+		return nil, errors.New("Empty collaborator or role while using Collaborate")
+	}
+	collab := &Collaboration{
+		Item:         &Entity{Type: "folder", Id: f.Id},
+		AccessibleBy: &Entity{Type: accessibleBy.Type, Id: accessibleBy.Id},
+		Role:         role,
+	}
+	opts.apply(collab)
+	reqBody, _ := json.Marshal(collab)
+	body, err := box.doRequest(ctx, "POST", "collaborations", nil, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	collab = &Collaboration{}
+	err = box.decode(body, collab)
+	return collab, err
+}
+
+// Update changes the role of the collaboration. Making a user owner of
+// a folder transfers the ownership of the folder to them. Note that
+// only Id is required apriori.
+//
+// Deprecated: Use box.Collaborations.Update.
+func (c *Collaboration) Update(ctx context.Context, box *Box, role string, options ...RequestOption) error {
+	return (&collaborationService{box}).update(ctx, c, role, nil, options)
+}
+
+// UpdateWith is Update also changing the given options, if any.
+func (c *Collaboration) UpdateWith(ctx context.Context, box *Box, role string, opts *CollaborationOptions, options ...RequestOption) error {
+	return (&collaborationService{box}).update(ctx, c, role, opts, options)
+}
+
+// update updates c, see Collaboration.UpdateWith.
+func (s *collaborationService) update(ctx context.Context, c *Collaboration, role string, opts *CollaborationOptions, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if c.Id == "" {
+		return errors.New("Empty id while using Update")
+	}
+	rawurl := fmt.Sprintf("collaborations/%s", c.Id)
+	collab := &Collaboration{Role: role}
+	opts.apply(collab)
+	reqBody, _ := json.Marshal(collab)
+	body, err := box.doRequest(ctx, "PUT", rawurl, nil, reqBody)
+	if err != nil {
+		return err
+	}
+	// Transferring the ownership answers without a body.
+	if len(body) == 0 {
+		c.Role = role
+		return nil
+	}
+	return box.decode(body, c)
+}
+
+// Delete removes the collaboration. Note that only Id is required
+// apriori.
+//
+// Deprecated: Use box.Collaborations.Delete.
+func (c *Collaboration) Delete(ctx context.Context, box *Box, options ...RequestOption) error {
+	return (&collaborationService{box}).delete(ctx, c, options)
+}
+
+// delete deletes c, see Collaboration.Delete.
+func (s *collaborationService) delete(ctx context.Context, c *Collaboration, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if c.Id == "" {
+		return errors.New("Empty id while using Delete")
+	}
+	rawurl := fmt.Sprintf("collaborations/%s", c.Id)
+	_, err := box.doRequest(ctx, "DELETE", rawurl, nil, nil)
+	return box.deleted(err)
+}
+
+// MoveAcrossUsers transfers the ownership of the folder to the given
+// user, who is first made co-owner then owner as Box requires. The
+// collaborations the folder had before are restored if the transfer
+// dropped any, and the previous owner keeps co-owner access. Note that
+// only Id is required apriori.
+func (f *Folder) MoveAcrossUsers(ctx context.Context, box *Box, userId string, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using MoveAcrossUsers")
+	}
+	if userId == "" {
+		return errors.New("Empty user id while using MoveAcrossUsers")
+	}
+	before, err := f.Collaborations(ctx, box)
+	if err != nil {
+		return err
+	}
+
+	var owner *Collaboration
+	for i := range before {
+		if c := &before[i]; c.AccessibleBy != nil && c.AccessibleBy.Id == userId {
+			owner = c
+		}
+	}
+	if owner == nil {
+		if owner, err = f.Collaborate(ctx, box, &Entity{Type: "user", Id: userId}, "co-owner"); err != nil {
+			return err
+		}
+	}
+	if err = owner.Update(ctx, box, "owner"); err != nil {
+		return err
+	}
+
+	after, err := f.Collaborations(ctx, box)
+	if err != nil {
+		return err
+	}
+	kept := map[string]bool{}
+	for _, c := range after {
+		if c.AccessibleBy != nil {
+			kept[c.AccessibleBy.Id] = true
+		}
+	}
+	for _, c := range before {
+		if c.AccessibleBy == nil || c.AccessibleBy.Id == userId || kept[c.AccessibleBy.Id] || c.Status == "rejected" {
+			continue
+		}
+		if _, err = f.Collaborate(ctx, box, c.AccessibleBy, c.Role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
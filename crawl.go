@@ -0,0 +1,117 @@
+package box
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// CrawlFolder is a folder left to list by a restartable walk.
+type CrawlFolder struct {
+	Id     string `json:"id"`               // The id of the folder.
+	Path   string `json:"path"`             // The path of the folder relative to the walked folder.
+	Marker string `json:"marker,omitempty"` // Where to continue listing the folder, if started.
+}
+
+// CrawlStore persists the progress of a walk so that it can be resumed
+// after an interruption. Implementations must be safe for concurrent
+// use.
+type CrawlStore interface {
+	// Pending returns the folders not yet completely listed. It is
+	// empty for a walk that never ran or ran to completion.
+	Pending() ([]CrawlFolder, error)
+	// Add records newly found folders. Folders already known are
+	// kept as they are.
+	Add(folders []CrawlFolder) error
+	// SetMarker records the position reached inside the folder.
+	SetMarker(id, marker string) error
+	// Done records the folder as completely listed.
+	Done(id string) error
+}
+
+// FileCrawlStore is a CrawlStore saving the progress as a json file,
+// rewritten after every change.
+type FileCrawlStore struct {
+	path    string
+	mu      sync.Mutex
+	pending map[string]CrawlFolder
+}
+
+// NewFileCrawlStore returns a store saving to the file at path,
+// loading the progress already saved there if any.
+func NewFileCrawlStore(path string) (*FileCrawlStore, error) {
+	s := &FileCrawlStore{path: path, pending: map[string]CrawlFolder{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var folders []CrawlFolder
+	if err = json.Unmarshal(data, &folders); err != nil {
+		return nil, err
+	}
+	for _, f := range folders {
+		s.pending[f.Id] = f
+	}
+	return s, nil
+}
+
+func (s *FileCrawlStore) Pending() ([]CrawlFolder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(), nil
+}
+
+func (s *FileCrawlStore) Add(folders []CrawlFolder) error {
+	if len(folders) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range folders {
+		if _, ok := s.pending[f.Id]; !ok {
+			s.pending[f.Id] = f
+		}
+	}
+	return s.save()
+}
+
+func (s *FileCrawlStore) SetMarker(id, marker string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f := s.pending[id]
+	f.Id = id
+	f.Marker = marker
+	s.pending[id] = f
+	return s.save()
+}
+
+func (s *FileCrawlStore) Done(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, id)
+	return s.save()
+}
+
+// list returns the pending folders. s.mu must be held.
+func (s *FileCrawlStore) list() []CrawlFolder {
+	folders := make([]CrawlFolder, 0, len(s.pending))
+	for _, f := range s.pending {
+		folders = append(folders, f)
+	}
+	return folders
+}
+
+// save writes the pending folders to a temporary file renamed over the
+// store file, so that an interruption never leaves it half written.
+// s.mu must be held.
+func (s *FileCrawlStore) save() error {
+	data, err := json.Marshal(s.list())
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
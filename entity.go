@@ -6,13 +6,20 @@ import (
 	"time"
 )
 
-//  Represents both mini folder and mini file.
+// Represents both mini folder and mini file.
 type Entity struct {
-	SequenceId string `json:"sequence_id,omitempty"` // A unique ID for use with the /events endpoint.
-	Name       string `json:"name,omitempty"`        // The name of the entity.
-	Id         string `json:"id,omitempty"`          // The id of the entity.
-	ETag       string `json:"etag,omitempty"`        // A unique string identifying the version of this entity.
-	Type       string `json:"type,omitempty"`        // Type of entity
+	SequenceId string   `json:"sequence_id,omitempty"` // A unique ID for use with the /events endpoint.
+	Name       string   `json:"name,omitempty"`        // The name of the entity.
+	Id         string   `json:"id,omitempty"`          // The id of the entity.
+	ETag       string   `json:"etag,omitempty"`        // A unique string identifying the version of this entity.
+	Type       string   `json:"type,omitempty"`        // Type of entity
+	Size       int64    `json:"size,omitempty"`        // Size of the entity in bytes, if requested.
+	Sha1       string   `json:"sha1,omitempty"`        // The sha1 hash of the file, if requested.
+	ModifiedAt *BoxTime `json:"modified_at,omitempty"` // When the entity was last modified, if requested.
+	TrashedAt  *BoxTime `json:"trashed_at,omitempty"`  // When the entity was moved to the trash, if requested.
+	PurgedAt   *BoxTime `json:"purged_at,omitempty"`   // When the entity will be permanently deleted, if requested.
+
+	FileVersion *FileVersion `json:"file_version,omitempty"` // The current version of a file.
 }
 
 // IsFolder checks if the given entity is a folder
@@ -97,18 +104,21 @@ type Permission struct {
 	Delete   bool `json:"can_delete,omitempty"`
 	Share    bool `json:"can_share,omitempty"`
 	SetShare bool `json:"can_set_share_access,omitempty"`
+	Edit     bool `json:"can_edit,omitempty"`
 }
 
 type Collection struct {
-	Count  int      `json:"total_count,omitempty"`
-	Entry  []Entity `json:"entries,omitempty"`
-	Limit  int      `json:"limit,omitempty"`
-	Offset int      `json:"offset,omitempty"`
+	Count  int64       `json:"total_count,omitempty"`
+	Entry  []Entity    `json:"entries,omitempty"`
+	Limit  int64       `json:"limit,omitempty"`
+	Offset int64       `json:"offset,omitempty"`
+	Order  []ListOrder `json:"order,omitempty"`
 }
 
 type BoxLock struct {
 	Id        string   `json:"id,omitempty"`
-	CreatedBy string   `json:"created_by,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	CreatedBy *Entity  `json:"created_by,omitempty"`
 	CreatedAt *BoxTime `json:"created_at,omitempty"`
 	ExpiresAt *BoxTime `json:"expires_at,omitempty"`
 	Download  bool     `json:"is_download_prevented,omitempty"`
@@ -120,8 +130,8 @@ type SharedObject struct {
 	VanityUrl     string      `json:"vanity_url,omitempty"`
 	HasPassword   bool        `json:"is_password_enabled,omitempty"`
 	UnsharedAt    *BoxTime    `json:"unshared_at,omitempty"`
-	DownloadCount int         `json:"download_count,omitempty"`
-	PreviewCount  int         `json:"preview_count,omitempty"`
+	DownloadCount int64       `json:"download_count,omitempty"`
+	PreviewCount  int64       `json:"preview_count,omitempty"`
 	Access        string      `json:"access,omitempty"`
 	Permission    *Permission `json:"permissions,omitempty"`
 }
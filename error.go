@@ -1,43 +1,180 @@
 package box
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 )
 
 type BoxError struct {
-	StatusCode int
-	Message    string
+	StatusCode  int
+	Message     string
+	Code        string          // Box error code, e.g. item_name_in_use.
+	RequestId   string          // Id of the failed request, to report to Box support.
+	HelpUrl     string          // Link to the documentation of the error.
+	ContextInfo json.RawMessage // Error specific details, e.g. the conflicting items.
+	Body        []byte          // Start of the raw response body, kept if the box has an ErrorBodySize.
+	Header      http.Header     // Headers of the response, such as Box-Request-Id or Retry-After.
 }
 
 func (e *BoxError) Error() string {
-	return fmt.Sprintf("%v : %v", e.StatusCode, e.Message)
+	s := fmt.Sprintf("%v : %v", e.StatusCode, e.Message)
+	var details []string
+	if e.Code != "" {
+		details = append(details, e.Code)
+	}
+	if e.RequestId != "" {
+		details = append(details, "request id "+e.RequestId)
+	}
+	if len(details) > 0 {
+		s += " (" + strings.Join(details, ", ") + ")"
+	}
+	if len(e.Body) > 0 {
+		s += fmt.Sprintf(" body: %q", e.Body)
+	}
+	return s
+}
+
+// Is makes errors.Is match a BoxError carrying details from the
+// response against the sentinel of its status code, e.g. CONFLICT.
+func (e *BoxError) Is(target error) bool {
+	t, ok := target.(*BoxError)
+	return ok && t.StatusCode == e.StatusCode
+}
+
+// Conflicts returns the items a CONFLICT error is about, as listed in
+// its context info.
+func (e *BoxError) Conflicts() ([]Entity, error) {
+	var info struct {
+		Conflicts json.RawMessage `json:"conflicts"`
+	}
+	if len(e.ContextInfo) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(e.ContextInfo, &info); err != nil || len(info.Conflicts) == 0 {
+		return nil, err
+	}
+	// Box sends either a single item or a list of them.
+	var conflicts []Entity
+	if err := json.Unmarshal(info.Conflicts, &conflicts); err == nil {
+		return conflicts, nil
+	}
+	var conflict Entity
+	if err := json.Unmarshal(info.Conflicts, &conflict); err != nil {
+		return nil, err
+	}
+	return []Entity{conflict}, nil
+}
+
+// NotFoundError is returned when the item does not exist or is not
+// visible to the user.
+type NotFoundError struct {
+	*BoxError
+}
+
+func (e *NotFoundError) Unwrap() error { return e.BoxError }
+
+// ConflictError is returned when an item with the same name already
+// exists or the item changed in the meantime. Conflicts tells which
+// item conflicts.
+type ConflictError struct {
+	*BoxError
+}
+
+func (e *ConflictError) Unwrap() error { return e.BoxError }
+
+// NotModifiedError is returned by a conditional Get when the item
+// still has the ETag it was asked with.
+type NotModifiedError struct {
+	*BoxError
 }
 
+func (e *NotModifiedError) Unwrap() error { return e.BoxError }
+
+// RateLimitError is returned when requests are sent faster than Box
+// allows and retries did not help.
+type RateLimitError struct {
+	*BoxError
+	RetryAfter time.Duration // How long Box asked to wait, if it did.
+}
+
+func (e *RateLimitError) Unwrap() error { return e.BoxError }
+
+// parseError returns the error of the status code, with the details
+// Box sent in the response body if it has any. Errors with a typed
+// counterpart, such as NotFoundError, are returned as such.
+func parseError(status int, header http.Header, body []byte) error {
+	e := parseBoxError(status, body)
+	e.Header = header
+	if e.RequestId == "" {
+		e.RequestId = header.Get("Box-Request-Id")
+	}
+	switch status {
+	case NOT_FOUND.StatusCode:
+		return &NotFoundError{e}
+	case CONFLICT.StatusCode:
+		return &ConflictError{e}
+	case TOO_MANY_REQUESTS.StatusCode:
+		after, _ := parseRetryAfter(header.Get("Retry-After"))
+		return &RateLimitError{e, after}
+	}
+	return e
+}
+
+// parseBoxError returns the BoxError of the status code filled with the
+// details of body.
+func parseBoxError(status int, body []byte) *BoxError {
+	e := *toError(status)
+	var details struct {
+		Code        string          `json:"code"`
+		Message     string          `json:"message"`
+		RequestId   string          `json:"request_id"`
+		HelpUrl     string          `json:"help_url"`
+		ContextInfo json.RawMessage `json:"context_info"`
+	}
+	if json.Unmarshal(body, &details) == nil {
+		if details.Message != "" {
+			e.Message = details.Message
+		}
+		e.Code = details.Code
+		e.RequestId = details.RequestId
+		e.HelpUrl = details.HelpUrl
+		e.ContextInfo = details.ContextInfo
+	}
+	return &e
+}
+
+// Success statuses are never returned as errors, their sentinels are
+// kept for compatibility.
 var (
-	SUCCESS    = &BoxError{200, "Success"}
-	CREATED    = &BoxError{201, "Created"}
-	ACCEPTED   = &BoxError{202, "Accepted"}
-	NO_CONTENT = &BoxError{204, "No Content"}
+	SUCCESS         = &BoxError{StatusCode: 200, Message: "Success"}
+	CREATED         = &BoxError{StatusCode: 201, Message: "Created"}
+	ACCEPTED        = &BoxError{StatusCode: 202, Message: "Accepted"}
+	NO_CONTENT      = &BoxError{StatusCode: 204, Message: "No Content"}
+	PARTIAL_CONTENT = &BoxError{StatusCode: 206, Message: "Partial Content"}
 )
 
 var (
-	REDIRECT     = &BoxError{302, "Redirect"}
-	NOT_MODIFIED = &BoxError{304, "Not Modified"}
+	REDIRECT     = &BoxError{StatusCode: 302, Message: "Redirect"}
+	NOT_MODIFIED = &BoxError{StatusCode: 304, Message: "Not Modified"}
 )
 
 var (
-	UNAUTHORIZED        = &BoxError{401, "Unauthorized"}        // Authorization failed
-	FORBIDDEN           = &BoxError{403, "Forbidden"}           // Not enough permission for the operation
-	NOT_FOUND           = &BoxError{404, "Not found"}           // Not Found
-	NOT_ALLOWED         = &BoxError{405, "Not allowed"}         // Method not allowed
-	CONFLICT            = &BoxError{409, "Conflict"}            // Same name item already exist
-	PRECONDITION_FAILED = &BoxError{412, "Precondition failed"} // Precondition (If match) failed
-	TOO_MANY_REQUESTS   = &BoxError{429, "Too many requests"}   // Too many requests
+	UNAUTHORIZED          = &BoxError{StatusCode: 401, Message: "Unauthorized"}          // Authorization failed
+	FORBIDDEN             = &BoxError{StatusCode: 403, Message: "Forbidden"}             // Not enough permission for the operation
+	NOT_FOUND             = &BoxError{StatusCode: 404, Message: "Not found"}             // Not Found
+	NOT_ALLOWED           = &BoxError{StatusCode: 405, Message: "Not allowed"}           // Method not allowed
+	CONFLICT              = &BoxError{StatusCode: 409, Message: "Conflict"}              // Same name item already exist
+	PRECONDITION_FAILED   = &BoxError{StatusCode: 412, Message: "Precondition failed"}   // Precondition (If match) failed
+	RANGE_NOT_SATISFIABLE = &BoxError{StatusCode: 416, Message: "Range not satisfiable"} // Range starts past the end of the file
+	TOO_MANY_REQUESTS     = &BoxError{StatusCode: 429, Message: "Too many requests"}     // Too many requests
 )
 
 var (
-	SERVER_ERROR = &BoxError{500, "Internal server error"} // Internal server error
-	UNAVAILABLE  = &BoxError{503, "Unavailable"}           // Unavailable
+	SERVER_ERROR = &BoxError{StatusCode: 500, Message: "Internal server error"} // Internal server error
+	UNAVAILABLE  = &BoxError{StatusCode: 503, Message: "Unavailable"}           // Unavailable
 )
 
 func toError(status int) *BoxError {
@@ -50,6 +187,8 @@ func toError(status int) *BoxError {
 		return ACCEPTED
 	case 204:
 		return NO_CONTENT
+	case 206:
+		return PARTIAL_CONTENT
 	case 302:
 		return REDIRECT
 	case 304:
@@ -66,6 +205,8 @@ func toError(status int) *BoxError {
 		return CONFLICT
 	case 412:
 		return PRECONDITION_FAILED
+	case 416:
+		return RANGE_NOT_SATISFIABLE
 	case 429:
 		return TOO_MANY_REQUESTS
 	case 500:
@@ -73,6 +214,61 @@ func toError(status int) *BoxError {
 	case 503:
 		return UNAVAILABLE
 	default:
-		return &BoxError{status, "Unknown error"}
+		return &BoxError{StatusCode: status, Message: "Unknown error"}
 	}
 }
+
+// AuthError is returned when the box has no usable token: it could not
+// be obtained or refreshed, or Box kept refusing it.
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("Authentication failed: %v", e.Err)
+}
+
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// ReadOnlyError is returned when a read only box is asked to send a
+// request which could modify content.
+type ReadOnlyError struct {
+	Method string
+	URL    string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("Read only client refused %v %v", e.Method, e.URL)
+}
+
+// PolicyError is returned when the policy of the box refuses a request.
+type PolicyError struct {
+	Method string
+	Path   string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("Policy refused %v %v", e.Method, e.Path)
+}
+
+// SharedLinkAccessError is returned, without sending the request, when
+// a shared link is asked with an access level the policies of the user
+// and the enterprise do not allow on the item.
+type SharedLinkAccessError struct {
+	Access  string
+	Allowed []string
+}
+
+func (e *SharedLinkAccessError) Error() string {
+	return fmt.Sprintf("Shared link access %v not allowed, only %v", e.Access, e.Allowed)
+}
+
+// BodyTooLargeError is returned instead of holding in memory a request
+// or response body larger than the MaxBodySize of the box.
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("Body larger than the limit of %v bytes", e.Limit)
+}
@@ -0,0 +1,21 @@
+package box
+
+import "testing"
+
+func TestBoxErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  *BoxError
+		want string
+	}{
+		{&BoxError{StatusCode: 404, Message: "Not Found"}, "404 : Not Found"},
+		{&BoxError{StatusCode: 409, Message: "Conflict", Code: "item_name_in_use"}, "409 : Conflict (item_name_in_use)"},
+		{&BoxError{StatusCode: 500, Message: "Error", RequestId: "abc"}, "500 : Error (request id abc)"},
+		{&BoxError{StatusCode: 409, Message: "Conflict", Code: "item_name_in_use", RequestId: "abc"}, "409 : Conflict (item_name_in_use, request id abc)"},
+		{&BoxError{StatusCode: 400, Message: "Bad Request", Body: []byte("{}")}, `400 : Bad Request body: "{}"`},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is an entry of the events stream of the user.
+type Event struct {
+	Type      string          `json:"type,omitempty"`       // Type of the object, always event.
+	EventId   string          `json:"event_id,omitempty"`   // The id of the event, the stream may repeat it.
+	EventType string          `json:"event_type,omitempty"` // What happened, e.g. ITEM_UPLOAD or ITEM_TRASH.
+	CreatedAt *BoxTime        `json:"created_at,omitempty"` // When the event happened.
+	CreatedBy *Entity         `json:"created_by,omitempty"` // The user who caused the event.
+	SessionId string          `json:"session_id,omitempty"` // The session of the user who caused the event.
+	Source    json.RawMessage `json:"source,omitempty"`     // The item the event happened on.
+}
+
+// EventSource is the part of the source of an event used to tell which
+// folder it happened in.
+type EventSource struct {
+	Id             string       `json:"id,omitempty"`              // The id of the item.
+	Type           string       `json:"type,omitempty"`            // Type of the item, e.g. file or folder.
+	Name           string       `json:"name,omitempty"`            // The name of the item.
+	Sha1           string       `json:"sha1,omitempty"`            // The sha1 hash of a file.
+	FileVersion    *FileVersion `json:"file_version,omitempty"`    // The version of a file the event is about.
+	Parent         *Entity      `json:"parent,omitempty"`          // The folder that contains the item.
+	PathCollection *Collection  `json:"path_collection,omitempty"` // The path of folders to the item, starting at the root.
+}
+
+// Item decodes the source of the event. It returns nil when the
+// source is not an item, e.g. for collaboration events.
+func (e *Event) Item() *EventSource {
+	var src EventSource
+	if len(e.Source) == 0 || json.Unmarshal(e.Source, &src) != nil || src.Id == "" {
+		return nil
+	}
+	return &src
+}
+
+// in checks if the item is the folder or lies anywhere under it.
+func (s *EventSource) in(folderId string) bool {
+	if s.Id == folderId && s.Type == "folder" {
+		return true
+	}
+	if s.Parent != nil && s.Parent.Id == folderId {
+		return true
+	}
+	if s.PathCollection != nil {
+		for i := range s.PathCollection.Entry {
+			if s.PathCollection.Entry[i].Id == folderId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EventPage is a page of the events stream.
+type EventPage struct {
+	ChunkSize          int64          `json:"chunk_size,omitempty"`           // The number of events in the page.
+	NextStreamPosition streamPosition `json:"next_stream_position,omitempty"` // Where the next page starts.
+	Entries            []Event        `json:"entries,omitempty"`              // The events of the page.
+}
+
+// streamPosition is a stream position, sent by Box either as a number
+// or as a string.
+type streamPosition string
+
+func (p *streamPosition) UnmarshalJSON(data []byte) error {
+	*p = streamPosition(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// Events returns the page of up to limit events of the user starting at
+// the given stream position, "now" to only get the events to come. A
+// limit of 0 lets Box choose the page size.
+func (box *Box) Events(ctx context.Context, position string, limit int, options ...RequestOption) (*EventPage, error) {
+	ctx = withCall(ctx, options)
+	params := url.Values{"stream_type": {"changes"}}
+	if position != "" {
+		params.Set("stream_position", position)
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	body, err := box.doRequest(ctx, "GET", "events", &params, nil)
+	if err != nil {
+		return nil, err
+	}
+	var page EventPage
+	err = box.decode(body, &page)
+	return &page, err
+}
+
+// eventPageSize is the number of events the Watcher asks for at once.
+const eventPageSize = 500
+
+// Watcher watches any number of folders over a single events stream,
+// so the load on the api does not grow with the number of watched
+// folders. All the watches share the polling budget of the Watcher.
+type Watcher struct {
+	box      *Box
+	limiter  *rateLimiter
+	mu       sync.Mutex
+	watches  map[int]*watch
+	next     int
+	position string
+}
+
+type watch struct {
+	folderId string
+	sink     EventSink
+}
+
+// EventSink receives the events of a Watcher or of a WebhookHandler,
+// so the same consumer serves both the polling and the webhook event
+// models.
+type EventSink interface {
+	// Publish handles the event. An error stops the Watcher, or makes
+	// the WebhookHandler ask Box to deliver the event again later.
+	Publish(ctx context.Context, e *Event) error
+}
+
+// EventFunc adapts a function to an EventSink.
+type EventFunc func(*Event)
+
+func (fn EventFunc) Publish(ctx context.Context, e *Event) error {
+	fn(e)
+	return nil
+}
+
+// EventChan is an EventSink sending the events on a channel. Publish
+// blocks until the event is received or ctx is done.
+type EventChan chan<- *Event
+
+func (c EventChan) Publish(ctx context.Context, e *Event) error {
+	select {
+	case c <- e:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NewWatcher returns a Watcher polling the events stream at most once
+// every interval, whatever the number of watched folders.
+func (box *Box) NewWatcher(interval time.Duration) *Watcher {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	return &Watcher{
+		box:     box,
+		limiter: newRateLimiter(float64(time.Second)/float64(interval), 1),
+		watches: make(map[int]*watch),
+	}
+}
+
+// Watch calls fn with the events of the items in the folder or
+// anywhere under it, until stop is called. fn is called from the
+// goroutine running Run, one event at a time.
+func (w *Watcher) Watch(folderId string, fn func(*Event)) (stop func()) {
+	return w.WatchSink(folderId, EventFunc(fn))
+}
+
+// WatchSink is Watch publishing the events to sink.
+func (w *Watcher) WatchSink(folderId string, sink EventSink) (stop func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.next
+	w.next++
+	w.watches[id] = &watch{folderId: folderId, sink: sink}
+	return func() {
+		w.mu.Lock()
+		delete(w.watches, id)
+		w.mu.Unlock()
+	}
+}
+
+// Run polls the events stream from now on and dispatches the events to
+// the watches until ctx is done, the stream fails or a sink fails. A
+// later Run resumes where the previous one stopped. Run must not be
+// called concurrently.
+func (w *Watcher) Run(ctx context.Context) error {
+	if w.position == "" {
+		w.position = "now"
+	}
+	// The stream may deliver an event more than once.
+	seen := make(map[string]bool)
+	for {
+		if err := w.limiter.wait(ctx); err != nil {
+			return err
+		}
+		page, err := w.box.Events(ctx, w.position, eventPageSize)
+		if err != nil {
+			return err
+		}
+		if len(seen) > 4*eventPageSize {
+			seen = make(map[string]bool)
+		}
+		for i := range page.Entries {
+			e := &page.Entries[i]
+			if e.EventId != "" {
+				if seen[e.EventId] {
+					continue
+				}
+				seen[e.EventId] = true
+			}
+			if err := w.dispatch(ctx, e); err != nil {
+				return err
+			}
+		}
+		if page.NextStreamPosition != "" {
+			w.position = string(page.NextStreamPosition)
+		}
+	}
+}
+
+// dispatch calls the watches of the folders the event happened in.
+func (w *Watcher) dispatch(ctx context.Context, e *Event) error {
+	src := e.Item()
+	if src == nil {
+		return nil
+	}
+	w.mu.Lock()
+	var sinks []EventSink
+	for _, wt := range w.watches {
+		if src.in(wt.folderId) {
+			sinks = append(sinks, wt.sink)
+		}
+	}
+	w.mu.Unlock()
+	for _, sink := range sinks {
+		if err := sink.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
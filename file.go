@@ -2,14 +2,22 @@ package box
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 type File struct {
@@ -19,11 +27,11 @@ type File struct {
 	Sha1              string        `json:"sha1,omitempty"`                // The sha1 hash of this file.
 	Name              string        `json:"name,omitempty"`                // The name of this file.
 	Description       string        `json:"description,omitempty"`         // The description of this file.
-	Size              int           `json:"size,omitempty"`                // Size of this file in bytes.
+	Size              int64         `json:"size,omitempty"`                // Size of this file in bytes.
 	PathCollection    *Collection   `json:"path_collection,omitempty"`     // The path of folders to this item, starting at the root.
 	CreatedAt         *BoxTime      `json:"created_at,omitempty"`          // When this file was created on Box’s servers.
 	ModifiedAt        *BoxTime      `json:"modified_at,omitempty"`         // When this file was last updated on the Box servers.
-	ThrashedAt        *BoxTime      `json:"thrashed_at,omitempty"`         // When this file was last moved to the trash.
+	ThrashedAt        *BoxTime      `json:"trashed_at,omitempty"`          // When this file was last moved to the trash.
 	PurgedAt          *BoxTime      `json:"purged_at,omitempty"`           // When this file will be permanently deleted.
 	ContentCreatedAt  *BoxTime      `json:"content_created_at,omitempty"`  // When the content of this file was created.
 	ContentModifiedAt *BoxTime      `json:"content_modified_at,omitempty"` // When the content of this file was last modified.
@@ -34,71 +42,219 @@ type File struct {
 	Parent            *Entity       `json:"parent,omitempty"`              // The folder containing this file.
 	ItemStatus        string        `json:"item_status,omitempty"`         // Whether this item is deleted or not.
 	VersionNumber     string        `json:"version_number,omitempty"`      // The version of the file.
-	CommentCount      int           `json:"comment_count,omitempty"`       // The number of comments on a file.
+	FileVersion       *FileVersion  `json:"file_version,omitempty"`        // The current version of the file, whose Id retention and sign operations need.
+	CommentCount      int64         `json:"comment_count,omitempty"`       // The number of comments on a file.
 	Permissions       *Permission   `json:"permissions,omitempty"`         // The permissions that the current user has on this file.
 	Tags              []string      `json:"tags,omitempty"`                // All tags applied to this file.
 	Lock              *BoxLock      `json:"lock,omitempty"`                // The lock held on the file.
 	Extension         string        `json:"extension,omitempty"`           // Indicates the suffix, when available, on the file.
+	EmbedLink         *EmbedLink    `json:"expiring_embed_link,omitempty"` // The expiring embed link of the file, if requested.
+
+	AllowedSharedLinkAccessLevels []string `json:"allowed_shared_link_access_levels,omitempty"` // The shared link access levels allowed on the file, if requested.
+	DispositionAt                 *BoxTime `json:"disposition_at,omitempty"`                    // When the retention of the file ends, if requested.
+	ExpiresAt                     *BoxTime `json:"expires_at,omitempty"`                        // When the file is moved to the trash by itself, if requested.
+	IsExternallyOwned             bool     `json:"is_externally_owned,omitempty"`               // Whether the file is owned outside the enterprise, if requested.
+	ContentType                   string   `json:"-"`                                           // The MIME type to upload the file with, detected when empty.
 }
 
-// Get populates the fields of the file struct. Node that only Id is
-// required apriori.
-func (f *File) Get(box *Box) error {
+// Get populates the fields of the file struct, only the ones of
+// WithFields if given, such as "shared_link" or non default fields.
+// Node that only Id is required apriori.
+//
+// Deprecated: Use box.Files.Get.
+func (f *File) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	got, err := box.Files.Get(ctx, f.Id, box.ifNoneMatchOptions(f.ETag, options)...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// get populates f, see File.Get.
+func (s *fileService) get(ctx context.Context, f *File, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Get")
 	}
 	rawurl := fmt.Sprintf("files/%s", f.Id)
-	body, err := box.doRequest("GET", rawurl, nil, nil)
+	body, err := box.doRequestHeader(ctx, "GET", rawurl, nil, box.ifNoneMatch(f.ETag), nil)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
 }
 
+// HasChangedSince reports whether the file changed since it had the
+// given etag, without fetching the whole file object. When it did,
+// the ETag of the file is updated. Note that only Id is required
+// apriori.
+func (f *File) HasChangedSince(ctx context.Context, box *Box, etag string, options ...RequestOption) (bool, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return false, errors.New("Empty id while using HasChangedSince")
+	}
+	rawurl := fmt.Sprintf("files/%s", f.Id)
+	return box.changedSince(ctx, rawurl, etag, f)
+}
+
 // Delete deletes the file. Note that only Id is required apriori.
-func (f *File) Delete(box *Box) error {
+//
+// Deprecated: Use box.Files.Delete.
+func (f *File) Delete(ctx context.Context, box *Box, options ...RequestOption) error {
+	return box.Files.Delete(ctx, f.Id, box.ifMatchOptions(f.ETag, options)...)
+}
+
+// delete deletes f, see File.Delete.
+func (s *fileService) delete(ctx context.Context, f *File, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Delete")
 	}
 
 	rawurl := fmt.Sprintf("files/%s", f.Id)
-	_, err := box.doRequest("DELETE", rawurl, nil, nil)
+	_, err := box.doRequestHeader(ctx, "DELETE", rawurl, nil, box.ifMatch(f.ETag), nil)
 
-	if err == NO_CONTENT {
-		return nil
-	}
-
-	return err
+	return box.deleted(err)
 }
 
 // Rename renames the file with the new name. Note that only Id is
 // required apriori. The file object is populated with all the
 // information after the call.
-func (f *File) Rename(box *Box, name string) error {
+//
+// Deprecated: Use box.Files.Rename.
+func (f *File) Rename(ctx context.Context, box *Box, name string, options ...RequestOption) error {
+	got, err := box.Files.Rename(ctx, f.Id, name, box.ifMatchOptions(f.ETag, options)...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// rename renames f, see File.Rename.
+func (s *fileService) rename(ctx context.Context, f *File, name string, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Rename")
 	}
 
+	name, err := box.checkName(name)
+	if err != nil {
+		return err
+	}
 	file := File{Name: name}
 	reqBody, _ := json.Marshal(file)
 
 	rawurl := fmt.Sprintf("files/%s", f.Id)
-	body, err := box.doRequest("PUT", rawurl, nil, reqBody)
+	body, err := box.doRequestHeader(ctx, "PUT", rawurl, nil, box.ifMatch(f.ETag), reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
 
 }
 
+// FileUpdate holds the attributes changed by File.Update. Nil fields
+// are left unchanged, so an empty description or an empty tag list
+// clears them.
+type FileUpdate struct {
+	Name             *string       // The new name of the file.
+	Description      *string       // The new description of the file.
+	Tags             []string      // The new tags of the file, replacing the old ones.
+	Parent           *Folder       // The folder to move the file to.
+	SharedLink       *SharedObject // The shared link to create or change.
+	RemoveSharedLink bool          // Whether to remove the shared link, SharedLink is then ignored.
+}
+
+// Update changes the given attributes of the file in one call. Note
+// that only Id is required apriori. The file is populated with all the
+// information after the call.
+//
+// Deprecated: Use box.Files.Update.
+func (f *File) Update(ctx context.Context, box *Box, upd *FileUpdate, options ...RequestOption) error {
+	got, err := box.Files.Update(ctx, f.Id, upd, box.ifMatchOptions(f.ETag, options)...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// update updates f, see File.Update.
+func (s *fileService) update(ctx context.Context, f *File, upd *FileUpdate, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using Update")
+	}
+
+	attrs := map[string]interface{}{}
+	if upd.Name != nil {
+		name, err := box.checkName(*upd.Name)
+		if err != nil {
+			return err
+		}
+		attrs["name"] = name
+	}
+	if upd.Description != nil {
+		attrs["description"] = *upd.Description
+	}
+	if upd.Tags != nil {
+		attrs["tags"] = upd.Tags
+	}
+	if upd.Parent != nil {
+		if upd.Parent.Id == "" {
+			return errors.New("Empty parent id while using Update")
+		}
+		attrs["parent"] = &Entity{Id: upd.Parent.Id}
+	}
+	if upd.RemoveSharedLink {
+		attrs["shared_link"] = nil
+	} else if upd.SharedLink != nil {
+		rawurl := fmt.Sprintf("files/%s", f.Id)
+		if err := box.checkSharedLinkAccess(ctx, rawurl, f.AllowedSharedLinkAccessLevels, upd.SharedLink.Access); err != nil {
+			return err
+		}
+		attrs["shared_link"] = upd.SharedLink
+	}
+	reqBody, _ := json.Marshal(attrs)
+
+	rawurl := fmt.Sprintf("files/%s", f.Id)
+	body, err := box.doRequestHeader(ctx, "PUT", rawurl, nil, box.ifMatch(f.ETag), reqBody)
+
+	if err == nil {
+		err = box.decode(body, f)
+		return err
+	}
+	return err
+}
+
 // Move moves the current file under the given parent. Note that only
 // Id is required apriori for both file and parent folder. The file
 // is populated with all the information after the call.
-func (f *File) Move(box *Box, parent *Folder) error {
+//
+// Deprecated: Use box.Files.Move.
+func (f *File) Move(ctx context.Context, box *Box, parent *Folder, options ...RequestOption) error {
+	got, err := box.Files.Move(ctx, f.Id, parent.Id, box.ifMatchOptions(f.ETag, options)...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// move moves f, see File.Move.
+func (s *fileService) move(ctx context.Context, f *File, parent *Folder, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" || parent.Id == "" {
 		return errors.New("Empty id while using Move")
 	}
@@ -107,20 +263,126 @@ func (f *File) Move(box *Box, parent *Folder) error {
 	reqBody, _ := json.Marshal(file)
 
 	rawurl := fmt.Sprintf("files/%s", f.Id)
-	body, err := box.doRequest("PUT", rawurl, nil, reqBody)
+	body, err := box.doRequestHeader(ctx, "PUT", rawurl, nil, box.ifMatch(f.ETag), reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
 
 }
 
+// EmbedLink is a short lived url embedding the Box preview of a file,
+// e.g. in an iframe.
+type EmbedLink struct {
+	Url   string `json:"url,omitempty"` // The url of the preview.
+	Token *struct {
+		AccessToken string `json:"access_token,omitempty"` // The token the url is authorized with.
+		ExpiresIn   int64  `json:"expires_in,omitempty"`   // The lifetime of the token in seconds.
+		TokenType   string `json:"token_type,omitempty"`   // The type of the token, always bearer.
+	} `json:"token,omitempty"` // The token of the link.
+	ExpiresAt time.Time `json:"-"` // When the link expires, set by File.EmbedLinkUrl.
+}
+
+// EmbedLinkUrl gets the expiring embed link of the file, populating its
+// EmbedLink, and returns it. The link lasts about a minute, it should
+// be fetched again for every page view. Note that only Id is required
+// apriori.
+func (f *File) EmbedLinkUrl(ctx context.Context, box *Box, options ...RequestOption) (*EmbedLink, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using EmbedLinkUrl")
+	}
+	// Not a conditional Get, the link is new every time.
+	rawurl := fmt.Sprintf("files/%s", f.Id)
+	body, err := box.doRequest(ctx, "GET", rawurl, fieldsParams([]string{"expiring_embed_link"}), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = box.decode(body, f); err != nil {
+		return nil, err
+	}
+	if f.EmbedLink == nil {
+		return nil, errors.New("No embed link returned for the file")
+	}
+	if f.EmbedLink.Token != nil && f.EmbedLink.Token.ExpiresIn > 0 {
+		f.EmbedLink.ExpiresAt = time.Now().Add(time.Duration(f.EmbedLink.Token.ExpiresIn) * time.Second)
+	}
+	return f.EmbedLink, nil
+}
+
+// LockOptions holds the settings of a lock taken with File.SetLock.
+type LockOptions struct {
+	ExpiresAt       time.Time // When the lock is released by itself, never if zero.
+	PreventDownload bool      // Whether others are prevented from downloading the file.
+}
+
+// SetLock locks the file, preventing others from changing it, with the
+// given options if any. Locking a locked file updates its lock. Note
+// that only Id is required apriori. The Lock of the file is populated
+// after the call.
+func (f *File) SetLock(ctx context.Context, box *Box, opts *LockOptions, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using SetLock")
+	}
+
+	lock := BoxLock{Type: "lock"}
+	if opts != nil {
+		if !opts.ExpiresAt.IsZero() {
+			expires := BoxTime(opts.ExpiresAt)
+			lock.ExpiresAt = &expires
+		}
+		lock.Download = opts.PreventDownload
+	}
+	reqBody, _ := json.Marshal(File{Lock: &lock})
+
+	return f.putLock(ctx, box, reqBody)
+}
+
+// Unlock releases the lock of the file. Note that only Id is required
+// apriori.
+func (f *File) Unlock(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using Unlock")
+	}
+
+	reqBody := []byte(`{"lock" : null }`)
+
+	if err := f.putLock(ctx, box, reqBody); err != nil {
+		return err
+	}
+	f.Lock = nil
+	return nil
+}
+
+// putLock sends the lock change and reads back the lock of the file.
+func (f *File) putLock(ctx context.Context, box *Box, reqBody []byte) error {
+	rawurl := fmt.Sprintf("files/%s", f.Id)
+	body, err := box.doRequestHeader(ctx, "PUT", rawurl, fieldsParams([]string{"lock"}), box.ifMatch(f.ETag), reqBody)
+
+	if err == nil {
+		err = box.decode(body, f)
+		return err
+	}
+	return err
+}
+
 // Copy copies the current file under the given parent. Note that only
 // Id is required apriori for both file and parent folder. The copied
 // file is returned after copy is successful.
-func (f *File) Copy(box *Box, parent *Folder) (*File, error) {
+//
+// Deprecated: Use box.Files.Copy.
+func (f *File) Copy(ctx context.Context, box *Box, parent *Folder, options ...RequestOption) (*File, error) {
+	return box.Files.Copy(ctx, f.Id, parent.Id, options...)
+}
+
+// copy copies f, see File.Copy.
+func (s *fileService) copy(ctx context.Context, f *File, parent *Folder, options []RequestOption) (*File, error) {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" || parent.Id == "" {
 		return nil, errors.New("Empty id while using Copy")
 	}
@@ -129,63 +391,304 @@ func (f *File) Copy(box *Box, parent *Folder) (*File, error) {
 	reqBody, _ := json.Marshal(file)
 
 	rawurl := fmt.Sprintf("files/%s/copy", f.Id)
-	body, err := box.doRequest("POST", rawurl, nil, reqBody)
+	body, err := box.doRequest(ctx, "POST", rawurl, nil, reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, &file)
+		err = box.decode(body, &file)
 		return &file, err
 	}
 	return nil, err
 
 }
 
+// maxDownloadResumes is the number of times Download re-requests the
+// content endpoint after a transfer broke off midway.
+const maxDownloadResumes = 3
+
 // Download downloads the file. Note that only file id is required
-// apriori.
-func (f *File) Download(box *Box, writer io.Writer) error {
+// apriori. Box serves the content through a pre-signed url which may
+// expire during long downloads, in which case the content endpoint is
+// requested again and the download resumes from the current offset.
+// See WithDownloadProgress to follow the transfer.
+//
+// Deprecated: Use box.Files.Download.
+func (f *File) Download(ctx context.Context, box *Box, writer io.Writer, options ...RequestOption) error {
+	return box.Files.Download(ctx, f.Id, writer, options...)
+}
+
+// download downloads f, see File.Download.
+func (s *fileService) download(ctx context.Context, f *File, writer io.Writer, options []RequestOption) error {
+	box := s.box
+	if f.Id == "" {
+		return errors.New("Empty id while using Download")
+	}
+	return f.DownloadRange(ctx, box, writer, 0, -1, options...)
+}
+
+// DownloadRange downloads the bytes of the file from start to end
+// included, or to the end of the file if end is negative, e.g. to
+// resume an interrupted download. It fails with RANGE_NOT_SATISFIABLE
+// when start is past the end of the file. Note that only file id is
+// required apriori.
+func (f *File) DownloadRange(ctx context.Context, box *Box, writer io.Writer, start, end int64, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using DownloadRange")
+	}
+	if start < 0 || (end >= 0 && end < start) {
+		return fmt.Errorf("Invalid range %d-%d while using DownloadRange", start, end)
+	}
+
+	ctx, endSpan := startSpan(ctx, "GET", fmt.Sprintf("files/%s/content", f.Id))
+	cw := &countingWriter{w: writer, total: -1, fn: downloadProgress(ctx)}
+	var err error
+	var deadline time.Time
+	for attempt := 0; ; attempt++ {
+		err = f.download(ctx, box, cw, start, end)
+		// Wait for a file Box is still preparing, see AcceptedTimeout.
+		var notReady *notReadyError
+		if errors.As(err, &notReady) && box.AcceptedTimeout > 0 {
+			if deadline.IsZero() {
+				deadline = time.Now().Add(box.AcceptedTimeout)
+			}
+			if time.Now().Before(deadline) {
+				if err = sleep(ctx, box.pollDelay(notReady.retryAfter)); err != nil {
+					endSpan(0, attempt, err)
+					return err
+				}
+				attempt-- // polls are not resumes
+				continue
+			}
+			err = ACCEPTED
+		}
+		// Only failures while reading the content can be resumed.
+		if err == nil || cw.err != nil || !cw.started || ctx.Err() != nil || attempt == maxDownloadResumes {
+			endSpan(0, attempt, err)
+			return err
+		}
+	}
+}
+
+// download requests the content of the file from start to end, end
+// being negative for the end of the file, skipping the bytes already
+// written to cw, and copies the rest of it to cw.
+func (f *File) download(ctx context.Context, box *Box, cw *countingWriter, start, end int64) error {
 	var request *http.Request
 	var response *http.Response
 	var err error
 
-	if f.Id == "" {
-		return errors.New("Empty id while using Download")
+	rawurl := joinURL(box.APIURL, fmt.Sprintf("files/%s/content", f.Id))
+
+	if request, err = http.NewRequestWithContext(ctx, "GET", rawurl, nil); err != nil {
+		return err
+	}
+	offset := start + cw.n
+	switch {
+	case end >= 0:
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, end))
+	case offset > 0:
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
 	}
 
-	rawurl := fmt.Sprintf("%s/files/%s/content", box.APIURL, f.Id)
+	if response, err = box.contentClient().Do(request); err != nil {
+		return err
+	}
 
-	if request, err = http.NewRequest("GET", rawurl, nil); err != nil {
+	defer closeResponse(response)
+
+	// Do not write error responses in place of the file content.
+	switch response.StatusCode {
+	case http.StatusOK:
+		// The range was ignored, skip the bytes before it.
+		if _, err = io.CopyN(ioutil.Discard, response.Body, offset); err != nil {
+			return err
+		}
+	case http.StatusPartialContent:
+	case http.StatusAccepted:
+		return &notReadyError{response.Header.Get("Retry-After")}
+	default:
+		// Read the error details sent by Box.
+		if _, err = box.getResponse(response); err == nil {
+			err = toError(response.StatusCode)
+		}
 		return err
 	}
 
-	if response, err = box.client().Do(request); err != nil {
+	// The length of a partial response is the rest of the range.
+	if response.ContentLength >= 0 {
+		cw.total = cw.n + response.ContentLength
+		if response.StatusCode == http.StatusOK {
+			cw.total -= offset
+		}
+	}
+	cw.started = true
+	if end >= 0 && response.StatusCode == http.StatusOK {
+		// The whole file is sent, stop at the end of the range.
+		cw.total = end - start + 1
+		_, err = io.CopyN(cw, response.Body, end-offset+1)
+		if err == io.EOF {
+			// The file ends before the range does.
+			err = nil
+		}
 		return err
 	}
+	_, err = io.Copy(cw, response.Body)
+
+	return err
+}
 
-	defer response.Body.Close()
+// notReadyError is returned by download when Box is still preparing
+// the content of the file.
+type notReadyError struct {
+	retryAfter string
+}
 
-	_, err = io.Copy(writer, response.Body)
+func (e *notReadyError) Error() string {
+	return "File is not ready for download yet"
+}
 
+// countingWriter counts the bytes written to the underlying writer and
+// remembers its last error, telling write failures apart from read
+// failures. It reports the count to fn if set.
+type countingWriter struct {
+	w       io.Writer
+	n       int64
+	err     error
+	started bool
+	total   int64
+	fn      ProgressFunc
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	if n > 0 && c.fn != nil {
+		c.fn(c.n, c.total)
+	}
+	return n, err
+}
+
+// DownloadFile downloads the file at the given file path. File will be
+// overwritten if it already exists, see ResumeDownloadFile to continue
+// an interrupted download instead. Note that only file id is required
+// apriori.
+func (f *File) DownloadFile(ctx context.Context, box *Box, path string, options ...RequestOption) error {
+	if f.Id == "" {
+		return errors.New("Empty id while using DownloadFile")
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = f.Download(ctx, box, out, options...)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
 	return err
+}
 
+// ResumeDownloadFile downloads the file at the given file path,
+// continuing from the content already there, e.g. after DownloadFile
+// or ResumeDownloadFile were interrupted. The content is kept only if
+// the file did not change on Box in the meantime: its etag must still
+// be the ETag of f when set, and the whole content must match the sha1
+// of the file on Box, else the file is downloaded again from the
+// start. The ETag, Size and Sha1 of f are updated first, so that f can
+// be resumed again. Note that only file id is required apriori.
+func (f *File) ResumeDownloadFile(ctx context.Context, box *Box, path string, options ...RequestOption) error {
+	if f.Id == "" {
+		return errors.New("Empty id while using ResumeDownloadFile")
+	}
+	current := &File{Id: f.Id}
+	if err := current.Get(ctx, box, WithFields("etag", "size", "sha1")); err != nil {
+		return err
+	}
+	changed := f.ETag != "" && f.ETag != current.ETag
+	f.ETag, f.Size, f.Sha1 = current.ETag, current.Size, current.Sha1
+
+	out, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	err = f.resumeDownload(ctx, box, out, changed, options)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
 }
 
-// Download downloads the file at the given file path. File will be
-// overwritten if it already exists. Note that only file id is
-// required apriori.
-func (f *File) DownloadFile(box *Box, path string) error {
-	out, err := os.Create("foo.txt")
-	defer out.Close()
+// resumeDownload downloads the rest of the file into out, or all of it
+// if changed or if the content does not match the sha1 of the file.
+func (f *File) resumeDownload(ctx context.Context, box *Box, out *os.File, changed bool, options []RequestOption) error {
+	info, err := out.Stat()
 	if err != nil {
 		return err
 	}
-	return f.Download(box, out)
+	offset := info.Size()
+	if changed || offset > f.Size {
+		offset = 0
+	}
+	for {
+		if err = out.Truncate(offset); err != nil {
+			return err
+		}
+		if _, err = out.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+		if offset < f.Size {
+			if err = f.DownloadRange(ctx, box, out, offset, -1, options...); err != nil {
+				return err
+			}
+		}
+		if f.Sha1 == "" {
+			return nil
+		}
+		if _, err = out.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		h := sha1.New()
+		if _, err = io.Copy(h, out); err != nil {
+			return err
+		}
+		sum := hex.EncodeToString(h.Sum(nil))
+		if sum == f.Sha1 {
+			return nil
+		}
+		if offset == 0 {
+			return &ChecksumError{Expected: f.Sha1, Actual: sum}
+		}
+		// The partial content was from another version of the file.
+		offset = 0
+	}
 }
 
 // Upload uploads the file (given by the reader) at the given file
 // path. The file name on the box server is taken from the Name
 // attribute of file object. After upload, it then fills the
-// information of the recently uploaded file in the file object. Note
-// that Id attribute is required for the parent folder.
-func (f *File) Upload(box *Box, reader io.Reader, parent *Folder) error {
+// information of the recently uploaded file in the file object. The
+// content is sent by the Uploader of the box, or else chosen by size,
+// see ChunkedUploadSize. See WithUploadProgress to follow the
+// transfer. Note that Id attribute is required for the parent folder.
+//
+// Deprecated: Use box.Files.Upload.
+func (f *File) Upload(ctx context.Context, box *Box, reader io.Reader, parent *Folder, options ...RequestOption) error {
+	if f.ContentType != "" {
+		options = append([]RequestOption{withContentType(f.ContentType)}, options...)
+	}
+	got, err := box.Files.Upload(ctx, parent.Id, f.Name, reader, options...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// upload uploads f, see File.Upload.
+func (s *fileService) upload(ctx context.Context, f *File, reader io.Reader, parent *Folder, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
+	var err error
 
 	// Check is f has name attribute and parent has id attribute
 	if f.Name == "" {
@@ -195,79 +698,141 @@ func (f *File) Upload(box *Box, reader io.Reader, parent *Folder) error {
 	if parent.Id == "" {
 		return errors.New("Empty parent id while using Upload")
 	}
-
-	// Set up multipart writer
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	part, err := writer.CreateFormFile("filename", f.Name)
-	if err != nil {
-		return err
+	if o := callOpts(ctx); o != nil && f.ContentType == "" {
+		f.ContentType = o.contentType
 	}
 
-	if _, err = io.Copy(part, reader); err != nil {
+	if f.Name, err = box.checkName(f.Name); err != nil {
 		return err
 	}
 
-	// Write parent id
-	writer.WriteField("parent_id", parent.Id)
+	size := readerSize(reader)
+	return box.uploader(size).Upload(ctx, box, f, reader, size, parent)
+}
 
+// postUpload sends the multipart upload request of the content read
+// from r, streaming its body. It returns the response body and status,
+// and the Retry-After header of the response.
+func (box *Box) postUpload(ctx context.Context, name, contentType string, r io.Reader, parentId string) ([]byte, int, string, error) {
 	// API url
-	rawurl := fmt.Sprintf("%s/files/content", box.APIUPLOADURL)
+	rawurl := joinURL(box.APIUPLOADURL, "files/content")
+
+	// Stream the multipart body instead of holding the file in memory
+	body, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
 
 	// Create mutlipart request
-	request, err := http.NewRequest("POST", rawurl, body)
+	request, err := http.NewRequestWithContext(ctx, "POST", rawurl, body)
 	if err != nil {
-		return err
+		return nil, 0, "", err
 	}
+	// Unblocks the writer if the body was not read to the end
+	defer body.Close()
 
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 
-	if err = writer.Close(); err != nil {
-		return err
-	}
-	// Was giving error without this as it was setting wrong content-length
+	// The length is unknown, send the body chunked
 	request.ContentLength = -1
 
+	go func() {
+		pw.CloseWithError(writeUpload(writer, name, contentType, r, parentId))
+	}()
+
 	// Get response
 	var response *http.Response
-	if response, err = box.client().Do(request); err != nil {
-		return err
+	if response, err = box.contentClient().Do(request); err != nil {
+		return nil, 0, "", err
 	}
-	defer response.Body.Close()
+	defer closeResponse(response)
 
 	// Get response body
-	var respBody []byte
-	if respBody, err = getResponse(response); err != nil && err != CREATED {
+	respBody, err := box.getResponse(response)
+	return respBody, response.StatusCode, response.Header.Get("Retry-After"), err
+}
+
+// writeUpload writes the multipart body of an upload of the content
+// read from r to writer.
+func writeUpload(writer *multipart.Writer, name, contentType string, r io.Reader, parentId string) error {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="filename"; filename="%s"`, quoteEscaper.Replace(name)))
+	h.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(h)
+	if err != nil {
 		return err
 	}
 
-	// All because of weird box's return format of response body
-	var m map[string]json.RawMessage
-	err = json.Unmarshal(respBody, &m)
-	if err != nil {
+	if _, err = io.Copy(part, r); err != nil {
 		return err
 	}
-	var fs []json.RawMessage
-	err = json.Unmarshal(m["entries"], &fs)
-	if err != nil {
+
+	// Write parent id
+	if err = writer.WriteField("parent_id", parentId); err != nil {
 		return err
 	}
-	if len(fs) != 1 {
-		return errors.New("Not enough returned argument")
+	return writer.Close()
+}
+
+// quoteEscaper escapes quotes in the file name of multipart headers.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// detectContentType returns the MIME type of the file content read
+// from r, from the extension of name or else from the first bytes of
+// the content. The returned reader replaces r.
+func detectContentType(name string, r io.Reader) (io.Reader, string, error) {
+	if ct := mime.TypeByExtension(filepath.Ext(name)); ct != "" {
+		return r, ct, nil
 	}
-	err = json.Unmarshal(fs[0], f)
-	if err != nil {
-		return err
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, "", err
 	}
-	return nil
+	buf = buf[:n]
+	return io.MultiReader(bytes.NewReader(buf), r), http.DetectContentType(buf), nil
+}
+
+// UploadTarget is the direct upload location handed out by the
+// upload preflight check.
+type UploadTarget struct {
+	UploadUrl   string `json:"upload_url,omitempty"`   // The url to upload the file content to.
+	UploadToken string `json:"upload_token,omitempty"` // The token authorizing the upload, if any.
+}
+
+// Preflight checks whether a file of the given size can be uploaded
+// under the given parent with the Name attribute of the file object,
+// and returns the url the content should be uploaded to. Web backends
+// can hand the target over to browsers so that the file bytes go
+// directly to Box. Note that Id attribute is required for the parent
+// folder.
+func (f *File) Preflight(ctx context.Context, box *Box, parent *Folder, size int64, options ...RequestOption) (*UploadTarget, error) {
+	ctx = withCall(ctx, options)
+	if f.Name == "" {
+		return nil, errors.New("Empty name while using Preflight")
+	}
+
+	if parent.Id == "" {
+		return nil, errors.New("Empty parent id while using Preflight")
+	}
+
+	file := File{Name: f.Name, Size: size, Parent: &Entity{Id: parent.Id}}
+	reqBody, _ := json.Marshal(file)
+
+	body, err := box.doRequest(ctx, "OPTIONS", "files/content", nil, reqBody)
+
+	if err == nil {
+		var target UploadTarget
+		err = box.decode(body, &target)
+		return &target, err
+	}
+	return nil, err
 }
 
 // UploadFile directly uploads the file on the box server. The name is
 // taken from the Name attribute of the file object (if it is empty,
 // file name is chosen). Note than only parent id is required apriori
 // for the parent folder.
-func (f *File) UploadFile(box *Box, path string, parent *Folder) error {
+func (f *File) UploadFile(ctx context.Context, box *Box, path string, parent *Folder, options ...RequestOption) error {
 	if f.Name == "" {
 		f.Name = filepath.Base(path)
 	}
@@ -276,5 +841,5 @@ func (f *File) UploadFile(box *Box, path string, parent *Folder) error {
 	if err != nil {
 		return err
 	}
-	return f.Upload(box, file, parent)
+	return f.Upload(ctx, box, file, parent, options...)
 }
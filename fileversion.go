@@ -0,0 +1,118 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FileVersion is a previous version of a file.
+type FileVersion struct {
+	Id         string   `json:"id,omitempty"`          // The id of the version.
+	Type       string   `json:"type,omitempty"`        // Type of the object, always file_version.
+	Sha1       string   `json:"sha1,omitempty"`        // The SHA1 hash of the version content.
+	Name       string   `json:"name,omitempty"`        // The name of the file at this version.
+	Size       int64    `json:"size,omitempty"`        // Size of the version in bytes.
+	CreatedAt  *BoxTime `json:"created_at,omitempty"`  // When the version was created.
+	ModifiedAt *BoxTime `json:"modified_at,omitempty"` // When the version was last modified.
+	ModifiedBy *Entity  `json:"modified_by,omitempty"` // The user who created the version.
+	TrashedAt  *BoxTime `json:"trashed_at,omitempty"`  // When the version was moved to the trash.
+	PurgedAt   *BoxTime `json:"purged_at,omitempty"`   // When the version will be permanently deleted.
+}
+
+// VersionsPage returns a single page of the previous versions of the
+// file with its paging information. The current version is not among
+// them. Note that only Id is required apriori.
+func (f *File) VersionsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[FileVersion], error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using VersionsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	rawurl := fmt.Sprintf("files/%s/versions", f.Id)
+	return listPage[FileVersion](ctx, box, rawurl, opts.values(), nil)
+}
+
+// Versions returns all the previous versions of the file, newest
+// first. Note that only Id is required apriori.
+//
+// Deprecated: Use box.Files.Versions.
+func (f *File) Versions(ctx context.Context, box *Box, options ...RequestOption) ([]FileVersion, error) {
+	return box.Files.Versions(ctx, f.Id, options...)
+}
+
+// versions lists the versions of f, see File.Versions.
+func (s *fileService) versions(ctx context.Context, f *File, options []RequestOption) ([]FileVersion, error) {
+	box := s.box
+	var versions []FileVersion
+	it := newIterator(ctx, &ListOptions{Limit: 1000}, func(ctx context.Context, opts *ListOptions) (*ListResult[FileVersion], error) {
+		return f.VersionsPage(ctx, box, opts, options...)
+	})
+	for it.Next() {
+		versions = append(versions, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].created().After(versions[j].created())
+	})
+	return versions, nil
+}
+
+// created returns when the version was created, or the zero time.
+func (v *FileVersion) created() time.Time {
+	switch {
+	case v.CreatedAt != nil:
+		return time.Time(*v.CreatedAt)
+	case v.ModifiedAt != nil:
+		return time.Time(*v.ModifiedAt)
+	}
+	return time.Time{}
+}
+
+// DeleteVersion moves the previous version of the file to the trash.
+// Note that only Id is required apriori.
+func (f *File) DeleteVersion(ctx context.Context, box *Box, versionId string, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" || versionId == "" {
+		return errors.New("Empty id while using DeleteVersion")
+	}
+	rawurl := fmt.Sprintf("files/%s/versions/%s", f.Id, versionId)
+	_, err := box.doRequest(ctx, "DELETE", rawurl, nil, nil)
+	return box.deleted(err)
+}
+
+// PruneVersions deletes the previous versions of the file but the keep
+// newest ones, e.g. to limit the storage used by versions. It returns
+// the versions deleted, which are moved to the trash. Note that only Id
+// is required apriori.
+func (f *File) PruneVersions(ctx context.Context, box *Box, keep int, options ...RequestOption) ([]FileVersion, error) {
+	ctx = withCall(ctx, options)
+	if keep < 0 {
+		return nil, errors.New("Negative number of versions to keep while using PruneVersions")
+	}
+	versions, err := f.Versions(ctx, box)
+	if err != nil {
+		return nil, err
+	}
+	var pruned []FileVersion
+	for _, v := range versions {
+		if v.TrashedAt != nil {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		if err = f.DeleteVersion(ctx, box, v.Id); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, v)
+	}
+	return pruned, nil
+}
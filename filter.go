@@ -0,0 +1,136 @@
+package box
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Filter holds the include and exclude rules deciding which items a
+// bulk operation acts on, so that walking, uploading and downloading
+// trees all filter the same way. The zero Filter matches everything.
+//
+// Patterns use the path.Match syntax and are matched against both the
+// slash separated path relative to the root of the operation and the
+// base name of the item, so "*.tmp" excludes temporary files at any
+// depth while "build/*" only excludes the top level build folder.
+type Filter struct {
+	Include []string // Patterns of paths to include. Empty includes everything.
+	Exclude []string // Patterns of paths to exclude, even if included.
+
+	Extensions []string // Extensions of files to include, e.g. ".pdf". Empty includes all.
+
+	MinSize int64 // Minimum size of files in bytes. Zero means no minimum.
+	MaxSize int64 // Maximum size of files in bytes. Zero means no maximum.
+
+	ModifiedAfter  time.Time // Only include files modified after. Zero means no bound.
+	ModifiedBefore time.Time // Only include files modified before. Zero means no bound.
+}
+
+// Validate checks that all the patterns of the filter are well formed.
+func (f *Filter) Validate() error {
+	if f == nil {
+		return nil
+	}
+	for _, patterns := range [][]string{f.Include, f.Exclude} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Match reports whether the item at the relative path p passes the
+// filter. For folders only the patterns are considered, and a folder
+// that does not match should not be descended into. Malformed patterns
+// never match, use Validate to detect them.
+func (f *Filter) Match(p string, isDir bool, size int64, modified time.Time) bool {
+	if f == nil {
+		return true
+	}
+	p = strings.TrimPrefix(filepath.ToSlash(p), "/")
+
+	if len(f.Include) > 0 && !isDir && !matchAny(f.Include, p) {
+		return false
+	}
+	if matchAny(f.Exclude, p) {
+		return false
+	}
+	if isDir {
+		return true
+	}
+
+	if len(f.Extensions) > 0 {
+		ext := strings.ToLower(path.Ext(p))
+		found := false
+		for _, e := range f.Extensions {
+			if !strings.HasPrefix(e, ".") {
+				e = "." + e
+			}
+			if strings.ToLower(e) == ext {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.MinSize > 0 && size < f.MinSize {
+		return false
+	}
+	if f.MaxSize > 0 && size > f.MaxSize {
+		return false
+	}
+
+	if !f.ModifiedAfter.IsZero() && !modified.After(f.ModifiedAfter) {
+		return false
+	}
+	if !f.ModifiedBefore.IsZero() && !modified.Before(f.ModifiedBefore) {
+		return false
+	}
+	return true
+}
+
+// MatchFile reports whether the remote file at the relative path p
+// passes the filter, using its size and content modification time.
+func (f *Filter) MatchFile(p string, file *File) bool {
+	var modified time.Time
+	if file.ContentModifiedAt != nil {
+		modified = time.Time(*file.ContentModifiedAt)
+	} else if file.ModifiedAt != nil {
+		modified = time.Time(*file.ModifiedAt)
+	}
+	return f.Match(p, false, file.Size, modified)
+}
+
+// MatchFolder reports whether the remote folder at the relative path p
+// passes the filter.
+func (f *Filter) MatchFolder(p string, folder *Folder) bool {
+	return f.Match(p, true, 0, time.Time{})
+}
+
+// MatchLocal reports whether the local file or directory at the
+// relative path p passes the filter.
+func (f *Filter) MatchLocal(p string, info os.FileInfo) bool {
+	return f.Match(p, info.IsDir(), info.Size(), info.ModTime())
+}
+
+// matchAny checks if p or its base name matches any of the patterns.
+func matchAny(patterns []string, p string) bool {
+	base := path.Base(p)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package box
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,11 +14,11 @@ type Folder struct {
 	ETag              string        `json:"etag,omitempty"`                // A unique string identifying the version of this folder.
 	Name              string        `json:"name,omitempty"`                // The name of this folder.
 	Description       string        `json:"description,omitempty"`         // The description of this folder.
-	Size              int           `json:"size,omitempty"`                // Size of this file in bytes.
+	Size              int64         `json:"size,omitempty"`                // Size of this file in bytes.
 	PathCollection    *Collection   `json:"path_collection,omitempty"`     // The path of folders to this item, starting at the root.
 	CreatedAt         *BoxTime      `json:"created_at,omitempty"`          // The time the folder was created.
 	ModifiedAt        *BoxTime      `json:"modified_at,omitempty"`         // The time the folder or its contents were last modified.
-	ThrashedAt        *BoxTime      `json:"thrashed_at,omitempty"`         // The time the folder or its contents were put in the trash.
+	ThrashedAt        *BoxTime      `json:"trashed_at,omitempty"`          // The time the folder or its contents were put in the trash.
 	PurgedAt          *BoxTime      `json:"purged_at,omitempty"`           // The time the folder or its contents were purged from the trash.
 	ContentCreatedAt  *BoxTime      `json:"content_created_at,omitempty"`  // The time the folder or its contents were originally created (according to the uploader).
 	ContentModifiedAt *BoxTime      `json:"content_modified_at,omitempty"` // The time the folder or its contents were last modified (according to the uploader).
@@ -33,13 +34,21 @@ type Folder struct {
 	SyncStatus        string        `json:"sync_status,omitempty"`         // Whether this folder will be synced by the Box sync clients or not. Can be
 	ItemCollection    *Collection   `json:"item_collection,omitempty"`     // A collection of mini file and folder objects contained in this folder.
 	FolderUploadEmail *UploadEmail  `json:"folder_upload_email,omitempty"` // The upload email address for this folder. Null if not set.
+
+	AllowedSharedLinkAccessLevels []string `json:"allowed_shared_link_access_levels,omitempty"` // The shared link access levels allowed on the folder, if requested.
 }
 
 // Items returns all items (folder or files) under the given
-// folder. It calls Get if the folder is not already populated.
-func (f *Folder) Items(box *Box) ([]Entity, error) {
+// folder. It calls Get if the folder is not already populated. When
+// WithFields is given, all the items are listed again with those
+// fields, e.g. "shared_link" to get the shared links of the items.
+func (f *Folder) Items(ctx context.Context, box *Box, options ...RequestOption) ([]Entity, error) {
+	ctx = withCall(ctx, options)
+	if fields := callFields(ctx); len(fields) > 0 {
+		return f.listItems(ctx, box, fields)
+	}
 	if f.ItemCollection == nil {
-		if err := f.Get(box); err != nil {
+		if err := f.Get(ctx, box, options...); err != nil {
 			return nil, err
 		}
 	}
@@ -47,75 +56,210 @@ func (f *Folder) Items(box *Box) ([]Entity, error) {
 	return f.ItemCollection.Entry, nil
 }
 
+// ItemCount returns the number of items in the folder without listing
+// them, reading the total of a single item page. Note that only Id is
+// required apriori.
+func (f *Folder) ItemCount(ctx context.Context, box *Box, options ...RequestOption) (int64, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return 0, errors.New("Empty id while using ItemCount")
+	}
+	page, err := f.ItemsPage(ctx, box, &ListOptions{Limit: 1, Fields: []string{"id"}})
+	if err != nil {
+		return 0, err
+	}
+	return page.TotalCount, nil
+}
+
+// listItems lists all the items of the folder with the given fields.
+func (f *Folder) listItems(ctx context.Context, box *Box, fields []string) ([]Entity, error) {
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using Items")
+	}
+	var items []Entity
+	rawurl := fmt.Sprintf("folders/%s/items", f.Id)
+	params := *fieldsParams(fields)
+	params.Set("usemarker", "true")
+	err := box.listAll(ctx, rawurl, params, nil, func(entries json.RawMessage) error {
+		var page []Entity
+		err := box.decode(entries, &page)
+		items = append(items, page...)
+		return err
+	})
+	return items, err
+}
+
 // Create creates a sub folder under the given folder. It returns the
 // created folder. Note that only Id of the parent folder is required
 // apriori.
-func (f *Folder) Create(box *Box, name string) (*Folder, error) {
+//
+// Deprecated: Use box.Folders.Create.
+func (f *Folder) Create(ctx context.Context, box *Box, name string, options ...RequestOption) (*Folder, error) {
+	return box.Folders.Create(ctx, f.Id, name, options...)
+}
+
+// create creates a sub folder of f, see Folder.Create.
+func (s *folderService) create(ctx context.Context, f *Folder, name string, options []RequestOption) (*Folder, error) {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return nil, errors.New("Empty id while using Create")
 	}
 
+	name, err := box.checkName(name)
+	if err != nil {
+		return nil, err
+	}
+
 	fold := Folder{Name: name, Parent: &Entity{Id: f.Id}}
 	reqBody, _ := json.Marshal(fold)
 
-	body, err := box.doRequest("POST", "folders", nil, reqBody)
+	body, err := box.doRequest(ctx, "POST", "folders", nil, reqBody)
 
-	if err != nil && err != CREATED {
+	if err != nil {
 		return nil, err
 	}
 
-	err = json.Unmarshal(body, &fold)
+	err = box.decode(body, &fold)
 	return &fold, err
 }
 
-// Get populates the fields of the struct. Node that only Id is
-// required apriori.
-func (f *Folder) Get(box *Box) error {
+// CreateOrGet creates the sub folder name like Create, or returns the
+// existing one if there is already a folder with that name, e.g.
+// created by a concurrent worker. Note that only Id of the parent
+// folder is required apriori.
+func (f *Folder) CreateOrGet(ctx context.Context, box *Box, name string, options ...RequestOption) (*Folder, error) {
+	fold, err := f.Create(ctx, box, name, options...)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		return fold, err
+	}
+
+	// Box tells which item is in the way.
+	if items, cerr := conflict.Conflicts(); cerr == nil {
+		for i := range items {
+			if items[i].IsFolder() {
+				fold = &Folder{Id: items[i].Id}
+				return fold, fold.Get(ctx, box, options...)
+			}
+		}
+		if len(items) > 0 {
+			return nil, err
+		}
+	}
+
+	// Otherwise look it up by name, listing the items afresh.
+	items, lerr := f.Items(ctx, box, WithFields("type", "id", "sequence_id", "etag", "name"))
+	if lerr != nil {
+		return nil, lerr
+	}
+	for i := range items {
+		if items[i].IsFolder() && box.sameName(items[i].Name, name) {
+			fold = &Folder{}
+			return fold, items[i].toFolder(fold)
+		}
+	}
+	return nil, err
+}
+
+// Get populates the fields of the struct, only the ones of WithFields
+// if given. Node that only Id is required apriori.
+//
+// Deprecated: Use box.Folders.Get.
+func (f *Folder) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	got, err := box.Folders.Get(ctx, f.Id, box.ifNoneMatchOptions(f.ETag, options)...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// get populates f, see Folder.Get.
+func (s *folderService) get(ctx context.Context, f *Folder, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Get")
 	}
 	rawurl := fmt.Sprintf("folders/%s", f.Id)
-	body, err := box.doRequest("GET", rawurl, nil, nil)
+	body, err := box.doRequestHeader(ctx, "GET", rawurl, nil, box.ifNoneMatch(f.ETag), nil)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
 }
 
+// HasChangedSince reports whether the folder changed since it had the
+// given etag, without fetching the whole folder object. When it did,
+// the ETag of the folder is updated. Note that only Id is required
+// apriori.
+func (f *Folder) HasChangedSince(ctx context.Context, box *Box, etag string, options ...RequestOption) (bool, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return false, errors.New("Empty id while using HasChangedSince")
+	}
+	rawurl := fmt.Sprintf("folders/%s", f.Id)
+	return box.changedSince(ctx, rawurl, etag, f)
+}
+
 // Delete deletes the folder. Note that only Id is required apriori.
-func (f *Folder) Delete(box *Box) error {
+//
+// Deprecated: Use box.Folders.Delete.
+func (f *Folder) Delete(ctx context.Context, box *Box, options ...RequestOption) error {
+	return box.Folders.Delete(ctx, f.Id, box.ifMatchOptions(f.ETag, options)...)
+}
+
+// delete deletes f, see Folder.Delete.
+func (s *folderService) delete(ctx context.Context, f *Folder, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Delete")
 	}
 
 	rawurl := fmt.Sprintf("folders/%s", f.Id)
-	_, err := box.doRequest("DELETE", rawurl, &url.Values{"recursive": {"true"}}, nil)
-
-	if err == NO_CONTENT {
-		return nil
-	}
+	_, err := box.doRequestHeader(ctx, "DELETE", rawurl, &url.Values{"recursive": {"true"}}, box.ifMatch(f.ETag), nil)
 
-	return err
+	return box.deleted(err)
 }
 
 // Rename renames the folder with the new name. Note that only Id is
 // required apriori. The folder is populated with all the information
 // after the call.
-func (f *Folder) Rename(box *Box, name string) error {
+//
+// Deprecated: Use box.Folders.Rename.
+func (f *Folder) Rename(ctx context.Context, box *Box, name string, options ...RequestOption) error {
+	got, err := box.Folders.Rename(ctx, f.Id, name, box.ifMatchOptions(f.ETag, options)...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// rename renames f, see Folder.Rename.
+func (s *folderService) rename(ctx context.Context, f *Folder, name string, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Rename")
 	}
 
+	name, err := box.checkName(name)
+	if err != nil {
+		return err
+	}
 	fold := Folder{Name: name}
 	reqBody, _ := json.Marshal(fold)
 
 	rawurl := fmt.Sprintf("folders/%s", f.Id)
-	body, err := box.doRequest("PUT", rawurl, nil, reqBody)
+	body, err := box.doRequestHeader(ctx, "PUT", rawurl, nil, box.ifMatch(f.ETag), reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
@@ -125,7 +269,21 @@ func (f *Folder) Rename(box *Box, name string) error {
 // Move moves the current folder under the given parent. Note that
 // only Id is required apriori for both parent and current folder. The
 // folder is populated with all the information after the call.
-func (f *Folder) Move(box *Box, parent *Folder) error {
+//
+// Deprecated: Use box.Folders.Move.
+func (f *Folder) Move(ctx context.Context, box *Box, parent *Folder, options ...RequestOption) error {
+	got, err := box.Folders.Move(ctx, f.Id, parent.Id, box.ifMatchOptions(f.ETag, options)...)
+	if err != nil {
+		return err
+	}
+	*f = *got
+	return nil
+}
+
+// move moves f, see Folder.Move.
+func (s *folderService) move(ctx context.Context, f *Folder, parent *Folder, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" || parent.Id == "" {
 		return errors.New("Empty id while using Move")
 	}
@@ -134,10 +292,10 @@ func (f *Folder) Move(box *Box, parent *Folder) error {
 	reqBody, _ := json.Marshal(fold)
 
 	rawurl := fmt.Sprintf("folders/%s", f.Id)
-	body, err := box.doRequest("PUT", rawurl, nil, reqBody)
+	body, err := box.doRequestHeader(ctx, "PUT", rawurl, nil, box.ifMatch(f.ETag), reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
@@ -147,7 +305,16 @@ func (f *Folder) Move(box *Box, parent *Folder) error {
 // Copy copies the current folder under the given parent. Note that
 // only Id is required apriori for both parent and current folder. The
 // copied folder is returned after copy is successful.
-func (f *Folder) Copy(box *Box, parent *Folder) (*Folder, error) {
+//
+// Deprecated: Use box.Folders.Copy.
+func (f *Folder) Copy(ctx context.Context, box *Box, parent *Folder, options ...RequestOption) (*Folder, error) {
+	return box.Folders.Copy(ctx, f.Id, parent.Id, options...)
+}
+
+// copy copies f, see Folder.Copy.
+func (s *folderService) copy(ctx context.Context, f *Folder, parent *Folder, options []RequestOption) (*Folder, error) {
+	box := s.box
+	ctx = withCall(ctx, options)
 	if f.Id == "" || parent.Id == "" {
 		return nil, errors.New("Empty id while using Copy")
 	}
@@ -156,10 +323,10 @@ func (f *Folder) Copy(box *Box, parent *Folder) (*Folder, error) {
 	reqBody, _ := json.Marshal(fold)
 
 	rawurl := fmt.Sprintf("folders/%s/copy", f.Id)
-	body, err := box.doRequest("POST", rawurl, nil, reqBody)
+	body, err := box.doRequest(ctx, "POST", rawurl, nil, reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, &fold)
+		err = box.decode(body, &fold)
 		return &fold, err
 	}
 	return nil, err
@@ -171,21 +338,25 @@ func (f *Folder) Copy(box *Box, parent *Folder) (*Folder, error) {
 // that only folder Id is required apriori. The folder is populated
 // with all the information after the call. You can get the
 // SharedObject by accessing appropriate field of the folder.
-func (f *Folder) Share(box *Box, download, preview bool) error {
+func (f *Folder) Share(ctx context.Context, box *Box, download, preview bool, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Share")
 	}
 
-	fold := Folder{SharedLink: &SharedObject{Access: "open",
+	fold := Folder{SharedLink: &SharedObject{Access: ACCESS_OPEN,
 		Permission: &Permission{Download: download, Preview: preview}}}
 
 	reqBody, _ := json.Marshal(fold)
 
 	rawurl := fmt.Sprintf("folders/%s", f.Id)
-	body, err := box.doRequest("PUT", rawurl, nil, reqBody)
+	if err := box.checkSharedLinkAccess(ctx, rawurl, f.AllowedSharedLinkAccessLevels, ACCESS_OPEN); err != nil {
+		return err
+	}
+	body, err := box.doRequest(ctx, "PUT", rawurl, nil, reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
@@ -193,7 +364,8 @@ func (f *Folder) Share(box *Box, download, preview bool) error {
 }
 
 // Unshare invalidates the shared link of the folder.
-func (f *Folder) Unshare(box *Box) error {
+func (f *Folder) Unshare(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
 	if f.Id == "" {
 		return errors.New("Empty id while using Share")
 	}
@@ -201,10 +373,10 @@ func (f *Folder) Unshare(box *Box) error {
 	reqBody := []byte(`{"shared_link" : null }`)
 
 	rawurl := fmt.Sprintf("folders/%s", f.Id)
-	body, err := box.doRequest("PUT", rawurl, nil, reqBody)
+	body, err := box.doRequest(ctx, "PUT", rawurl, nil, reqBody)
 
 	if err == nil {
-		err = json.Unmarshal(body, f)
+		err = box.decode(body, f)
 		return err
 	}
 	return err
@@ -0,0 +1,119 @@
+package box
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// EndpointRule matches requests by method and api path.
+type EndpointRule struct {
+	Method string // The http method, empty or "*" for any.
+	Path   string // A path.Match pattern of the path relative to the api root, e.g. "users/*".
+}
+
+// match checks if the rule matches the request. A pattern also matches
+// everything below it, so "users" matches "users/me/memberships".
+func (r EndpointRule) match(method, p string) bool {
+	if r.Method != "" && r.Method != "*" && !strings.EqualFold(r.Method, method) {
+		return false
+	}
+	pattern := strings.Trim(r.Path, "/")
+	if ok, _ := path.Match(pattern, p); ok {
+		return true
+	}
+	for dir := p; dir != "." && dir != "/" && dir != ""; dir = path.Dir(dir) {
+		if ok, _ := path.Match(pattern, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EndpointPolicy allows or denies requests by endpoint. A request is
+// refused if it matches a Deny rule, or if there are Allow rules and it
+// matches none of them. Its Check method can be used as the Policy of
+// a box.
+type EndpointPolicy struct {
+	Allow []EndpointRule
+	Deny  []EndpointRule
+}
+
+// Check returns a PolicyError if the policy refuses the request.
+func (p *EndpointPolicy) Check(method, path string) error {
+	for _, r := range p.Deny {
+		if r.match(method, path) {
+			return &PolicyError{Method: method, Path: path}
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, r := range p.Allow {
+		if r.match(method, path) {
+			return nil
+		}
+	}
+	return &PolicyError{Method: method, Path: path}
+}
+
+// guardTransport refuses the requests the box is not allowed to send
+// before they reach the wrapped transport.
+type guardTransport struct {
+	box  *Box
+	next http.RoundTripper
+}
+
+func (t *guardTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var err error
+	if t.box.ReadOnly && !safeMethod(req.Method) {
+		err = &ReadOnlyError{Method: req.Method, URL: req.URL.String()}
+	} else if t.box.Policy != nil {
+		if p, ok := t.box.apiPath(req.URL); ok {
+			err = t.box.Policy(req.Method, p)
+		}
+	}
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// apiPath returns the path of u relative to the api or upload root. It
+// fails for urls outside of them, such as download redirects.
+func (box *Box) apiPath(u *url.URL) (string, bool) {
+	for _, root := range []string{box.APIURL, box.APIUPLOADURL} {
+		base, err := url.Parse(root)
+		if err != nil || base.Host != u.Host {
+			continue
+		}
+		prefix := strings.TrimSuffix(base.Path, "/") + "/"
+		if strings.HasPrefix(u.Path, prefix) {
+			return strings.Trim(strings.TrimPrefix(u.Path, prefix), "/"), true
+		}
+	}
+	return "", false
+}
+
+// idempotent checks if sending a request with the method twice has the
+// same effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// safeMethod checks if requests with the method never modify content.
+func safeMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package box
+
+import (
+	"net/http"
+)
+
+// WithHeader makes the requests of the call, uploads and downloads
+// included, carry the given headers, e.g. X-Rep-Hints, without
+// changing the box. They take precedence over the headers the box sets
+// itself, but for the authorization. Several WithHeader add up.
+func WithHeader(header http.Header) RequestOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		for k, v := range header {
+			k = http.CanonicalHeaderKey(k)
+			o.header[k] = append(o.header[k], v...)
+		}
+	}
+}
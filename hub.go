@@ -0,0 +1,172 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type Hub struct {
+	Id                 string   `json:"id,omitempty"`                                        // The hub’s ID.
+	Type               string   `json:"type,omitempty"`                                      // Type of the object, always hubs.
+	Title              string   `json:"title,omitempty"`                                     // The title of this hub.
+	Description        string   `json:"description,omitempty"`                               // The description of this hub.
+	CreatedAt          *BoxTime `json:"created_at,omitempty"`                                // When this hub was created.
+	UpdatedAt          *BoxTime `json:"updated_at,omitempty"`                                // When this hub was last updated.
+	CreatedBy          *Entity  `json:"created_by,omitempty"`                                // The user who created this hub.
+	UpdatedBy          *Entity  `json:"updated_by,omitempty"`                                // The user who last updated this hub.
+	ViewCount          int64    `json:"view_count,omitempty"`                                // The number of views of this hub.
+	AIEnabled          bool     `json:"is_ai_enabled,omitempty"`                             // Whether Box AI is enabled for this hub.
+	EnterpriseOnly     bool     `json:"is_collaboration_restricted_to_enterprise,omitempty"` // Whether collaborators are restricted to the enterprise.
+	NonOwnersCanInvite bool     `json:"can_non_owners_invite,omitempty"`                     // Whether non owners can invite collaborators.
+	SharedLinkAllowed  bool     `json:"can_shared_link_be_created,omitempty"`                // Whether a shared link can be created for this hub.
+}
+
+// hubItemOperation is a single add or remove operation on the items of
+// a hub.
+type hubItemOperation struct {
+	Action string  `json:"action"`
+	Item   *Entity `json:"item"`
+}
+
+// hubHeader returns the headers required by the hubs endpoints.
+func hubHeader() http.Header {
+	return http.Header{"Box-Version": {hubsVersion}}
+}
+
+// Create creates a new hub with the Title and Description of the hub
+// object. The hub is populated with all the information after the
+// call.
+func (h *Hub) Create(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if h.Title == "" {
+		return errors.New("Empty title while using Create")
+	}
+
+	hub := Hub{Title: h.Title, Description: h.Description}
+	reqBody, _ := json.Marshal(hub)
+
+	body, err := box.doRequestHeader(ctx, "POST", "hubs", nil, hubHeader(), reqBody)
+
+	if err != nil {
+		return err
+	}
+
+	return box.decode(body, h)
+}
+
+// Get populates the fields of the hub, only the ones of WithFields if
+// given. Note that only Id is required apriori.
+func (h *Hub) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if h.Id == "" {
+		return errors.New("Empty id while using Get")
+	}
+	rawurl := fmt.Sprintf("hubs/%s", h.Id)
+	body, err := box.doRequestHeader(ctx, "GET", rawurl, nil, hubHeader(), nil)
+
+	if err == nil {
+		err = box.decode(body, h)
+		return err
+	}
+	return err
+}
+
+// HubUpdate holds the title, description and settings changed by
+// Hub.Update. Nil fields are left unchanged, so a setting can be
+// switched off, see Bool.
+type HubUpdate struct {
+	Title              *string `json:"title,omitempty"`                                     // The new title of the hub.
+	Description        *string `json:"description,omitempty"`                               // The new description of the hub.
+	AIEnabled          *bool   `json:"is_ai_enabled,omitempty"`                             // Whether Box AI is enabled for the hub.
+	EnterpriseOnly     *bool   `json:"is_collaboration_restricted_to_enterprise,omitempty"` // Whether collaborators are restricted to the enterprise.
+	NonOwnersCanInvite *bool   `json:"can_non_owners_invite,omitempty"`                     // Whether non owners can invite collaborators.
+	SharedLinkAllowed  *bool   `json:"can_shared_link_be_created,omitempty"`                // Whether a shared link can be created for the hub.
+}
+
+// Update changes the given title, description and settings of the hub.
+// Note that only Id is required apriori. The hub is populated with all
+// the information after the call.
+func (h *Hub) Update(ctx context.Context, box *Box, upd *HubUpdate, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if h.Id == "" {
+		return errors.New("Empty id while using Update")
+	}
+
+	reqBody, _ := json.Marshal(upd)
+
+	rawurl := fmt.Sprintf("hubs/%s", h.Id)
+	body, err := box.doRequestHeader(ctx, "PUT", rawurl, nil, hubHeader(), reqBody)
+
+	if err == nil {
+		err = box.decode(body, h)
+		return err
+	}
+	return err
+}
+
+// Delete deletes the hub. Note that only Id is required apriori.
+func (h *Hub) Delete(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if h.Id == "" {
+		return errors.New("Empty id while using Delete")
+	}
+
+	rawurl := fmt.Sprintf("hubs/%s", h.Id)
+	_, err := box.doRequestHeader(ctx, "DELETE", rawurl, nil, hubHeader(), nil)
+
+	return box.deleted(err)
+}
+
+// Items returns the files, folders and web links curated in the
+// hub. Note that only Id is required apriori.
+func (h *Hub) Items(ctx context.Context, box *Box, options ...RequestOption) ([]Entity, error) {
+	ctx = withCall(ctx, options)
+	if h.Id == "" {
+		return nil, errors.New("Empty id while using Items")
+	}
+
+	var items []Entity
+	it := h.ItemsIterator(ctx, box, &ListOptions{Limit: callLimit(ctx, 1000)}, options...)
+	for it.Next() {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// AddItems adds the given files, folders or web links to the hub. Only
+// Id and Type are required for the items.
+func (h *Hub) AddItems(ctx context.Context, box *Box, items []Entity, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if h.Id == "" {
+		return errors.New("Empty id while using AddItems")
+	}
+	return h.manageItems(ctx, box, "add", items)
+}
+
+// RemoveItems removes the given files, folders or web links from the
+// hub. Only Id and Type are required for the items.
+func (h *Hub) RemoveItems(ctx context.Context, box *Box, items []Entity, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if h.Id == "" {
+		return errors.New("Empty id while using RemoveItems")
+	}
+	return h.manageItems(ctx, box, "remove", items)
+}
+
+// manageItems applies the action to all the items of the hub.
+func (h *Hub) manageItems(ctx context.Context, box *Box, action string, items []Entity) error {
+	ops := make([]hubItemOperation, len(items))
+	for i := range items {
+		ops[i] = hubItemOperation{Action: action,
+			Item: &Entity{Id: items[i].Id, Type: items[i].Type}}
+	}
+	reqBody, _ := json.Marshal(map[string][]hubItemOperation{"operations": ops})
+
+	rawurl := fmt.Sprintf("hubs/%s/manage_items", h.Id)
+	_, err := box.doRequestHeader(ctx, "POST", rawurl, nil, hubHeader(), reqBody)
+
+	return err
+}
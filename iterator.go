@@ -0,0 +1,138 @@
+package box
+
+import "context"
+
+// Iterator walks the entries of a listing, getting its pages as
+// needed, whether the listing is paged with offsets or markers:
+//
+//	it := folder.ItemsIterator(ctx, box, nil)
+//	for it.Next() {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, opts *ListOptions) (*ListResult[T], error)
+	opts  ListOptions
+	page  []T
+	i     int
+	done  bool
+	err   error
+}
+
+// newIterator returns an iterator getting pages with fetch, starting
+// at the page selected by opts.
+func newIterator[T any](ctx context.Context, opts *ListOptions, fetch func(ctx context.Context, opts *ListOptions) (*ListResult[T], error)) *Iterator[T] {
+	it := &Iterator[T]{ctx: ctx, fetch: fetch, i: -1}
+	if opts != nil {
+		it.opts = *opts
+	}
+	return it
+}
+
+// Next advances to the next entry, getting the next page if needed. It
+// returns false at the end of the listing or on error, see Err.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.i+1 >= len(it.page) {
+		if it.done {
+			return false
+		}
+		page, err := it.fetch(it.ctx, &it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.i = page.Entries, -1
+		switch {
+		case page.NextMarker != "":
+			it.opts.Marker = page.NextMarker
+		case it.opts.UseMarker || it.opts.Marker != "":
+			it.done = true
+		default:
+			it.opts.Offset += int64(len(page.Entries))
+			it.done = len(page.Entries) == 0 || it.opts.Offset >= page.TotalCount
+		}
+	}
+	it.i++
+	return true
+}
+
+// Item returns the current entry.
+func (it *Iterator[T]) Item() T {
+	return it.page[it.i]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// ItemsIterator returns an iterator over the items of the folder,
+// starting at the page selected by opts. Note that only Id is required
+// apriori.
+//
+// Deprecated: Use box.Folders.Items.
+func (f *Folder) ItemsIterator(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) *Iterator[Entity] {
+	return box.Folders.Items(ctx, f.Id, opts, options...)
+}
+
+// items iterates over the items of f, see Folder.ItemsIterator.
+func (s *folderService) items(ctx context.Context, f *Folder, opts *ListOptions, options []RequestOption) *Iterator[Entity] {
+	return newIterator(ctx, opts, func(ctx context.Context, opts *ListOptions) (*ListResult[Entity], error) {
+		return s.itemsPage(ctx, f, opts, options)
+	})
+}
+
+// ItemsIterator returns an iterator over the items of the hub. Note
+// that only Id is required apriori.
+func (h *Hub) ItemsIterator(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) *Iterator[Entity] {
+	return newIterator(ctx, opts, func(ctx context.Context, opts *ListOptions) (*ListResult[Entity], error) {
+		return h.ItemsPage(ctx, box, opts, options...)
+	})
+}
+
+// CollaborationsIterator returns an iterator over the collaborations
+// of the folder. Note that only Id is required apriori.
+func (f *Folder) CollaborationsIterator(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) *Iterator[Collaboration] {
+	return newIterator(ctx, opts, func(ctx context.Context, opts *ListOptions) (*ListResult[Collaboration], error) {
+		return f.CollaborationsPage(ctx, box, opts, options...)
+	})
+}
+
+// SearchIterator returns an iterator over the results of the search,
+// see SearchPage.
+func (box *Box) SearchIterator(ctx context.Context, query string, filter *SearchFilter, opts *ListOptions, options ...RequestOption) *Iterator[Entity] {
+	return newIterator(ctx, opts, func(ctx context.Context, opts *ListOptions) (*ListResult[Entity], error) {
+		return box.SearchPage(ctx, query, filter, opts, options...)
+	})
+}
+
+// TrashIterator returns an iterator over the items in the trash.
+func (box *Box) TrashIterator(ctx context.Context, opts *ListOptions, options ...RequestOption) *Iterator[Entity] {
+	return newIterator(ctx, opts, func(ctx context.Context, opts *ListOptions) (*ListResult[Entity], error) {
+		return box.TrashPage(ctx, opts, options...)
+	})
+}
+
+// UsersPage returns a single page of the users of the enterprise with
+// its paging information.
+func (box *Box) UsersPage(ctx context.Context, opts *ListOptions, options ...RequestOption) (*ListResult[User], error) {
+	ctx = withCall(ctx, options)
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	return listPage[User](ctx, box, "users", opts.values(), nil)
+}
+
+// UsersIterator returns an iterator over the users of the enterprise.
+func (box *Box) UsersIterator(ctx context.Context, opts *ListOptions, options ...RequestOption) *Iterator[User] {
+	return newIterator(ctx, opts, func(ctx context.Context, opts *ListOptions) (*ListResult[User], error) {
+		return box.UsersPage(ctx, opts, options...)
+	})
+}
@@ -0,0 +1,319 @@
+package box
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"golang.org/x/oauth2"
+	"hash"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// jwtLifetime is the lifetime of the JWT assertions, Box accepts at
+// most a minute.
+const jwtLifetime = 45 * time.Second
+
+// JWTConfig is the configuration of a Box app using server
+// authentication, as downloaded from the developer console.
+type JWTConfig struct {
+	BoxAppSettings struct {
+		ClientID     string `json:"clientID,omitempty"`     // Client id of the app.
+		ClientSecret string `json:"clientSecret,omitempty"` // Client secret of the app.
+		AppAuth      struct {
+			PublicKeyID string `json:"publicKeyID,omitempty"` // Id of the public key registered with Box.
+			PrivateKey  string `json:"privateKey,omitempty"`  // PEM encoded private key.
+			Passphrase  string `json:"passphrase,omitempty"`  // Passphrase of the private key, if encrypted.
+		} `json:"appAuth,omitempty"`
+	} `json:"boxAppSettings,omitempty"`
+	EnterpriseID string `json:"enterpriseID,omitempty"` // Enterprise the app is installed in.
+}
+
+// NewBoxJWT gets a new Box object authenticated as the service account
+// of the enterprise of the app described by config, the JSON
+// configuration downloaded from the developer console. Tokens are
+// requested with JWT assertions and renewed automatically.
+func NewBoxJWT(config []byte) (*Box, error) {
+	return newBoxJWT(config, "enterprise", "")
+}
+
+// NewBoxJWTUser is NewBoxJWT authenticating as the given user, e.g. an
+// app user, instead of the service account.
+func NewBoxJWTUser(config []byte, userId string) (*Box, error) {
+	if userId == "" {
+		return nil, errors.New("Empty user id while using NewBoxJWTUser")
+	}
+	return newBoxJWT(config, "user", userId)
+}
+
+func newBoxJWT(config []byte, subType, subId string) (*Box, error) {
+	var c JWTConfig
+	if err := json.Unmarshal(config, &c); err != nil {
+		return nil, err
+	}
+	settings := c.BoxAppSettings
+	if settings.ClientID == "" || settings.ClientSecret == "" {
+		return nil, errors.New("Empty client id or secret in JWT config")
+	}
+	if settings.AppAuth.PublicKeyID == "" {
+		return nil, errors.New("Empty public key id in JWT config")
+	}
+	if subType == "enterprise" {
+		if c.EnterpriseID == "" {
+			return nil, errors.New("Empty enterprise id in JWT config")
+		}
+		subId = c.EnterpriseID
+	}
+	key, err := parsePrivateKey([]byte(settings.AppAuth.PrivateKey), []byte(settings.AppAuth.Passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	box := NewBox()
+	box.setRenewingSource(&jwtSource{
+		box:     box,
+		config:  &c,
+		key:     key,
+		subType: subType,
+		subId:   subId,
+	})
+	box.userBox = func(userId string) (*Box, error) {
+		return newBoxJWT(config, "user", userId)
+	}
+	return box, nil
+}
+
+// jwtSource gets tokens with the JWT assertion grant.
+type jwtSource struct {
+	box     *Box
+	config  *JWTConfig
+	key     *rsa.PrivateKey
+	subType string // "enterprise" or "user"
+	subId   string
+	skew    int64 // how far the clock of Box is ahead, in nanoseconds
+}
+
+// renewToken requests a token with a JWT assertion. When Box rejects
+// the assertion because of its exp or iat claim, the local clock is
+// off: the assertion is made again once from the Date of the response,
+// and the skew is kept for the next tokens.
+func (s *jwtSource) renewToken(ctx context.Context) (*oauth2.Token, error) {
+	now := time.Now().Add(time.Duration(atomic.LoadInt64(&s.skew)))
+	token, err := s.token(ctx, now)
+	var e *oauth2.RetrieveError
+	if !errors.As(err, &e) || !isClockSkew(e) {
+		return token, err
+	}
+	date, derr := http.ParseTime(e.Response.Header.Get("Date"))
+	if derr != nil {
+		return nil, err
+	}
+	atomic.StoreInt64(&s.skew, int64(time.Until(date)))
+	return s.token(ctx, date)
+}
+
+// token requests a token with an assertion issued at now.
+func (s *jwtSource) token(ctx context.Context, now time.Time) (*oauth2.Token, error) {
+	assertion, err := s.assertion(now)
+	if err != nil {
+		return nil, err
+	}
+	settings := s.config.BoxAppSettings
+	return s.box.requestToken(ctx, url.Values{
+		"grant_type":    {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":     {assertion},
+		"client_id":     {settings.ClientID},
+		"client_secret": {settings.ClientSecret},
+	})
+}
+
+// isClockSkew checks if Box rejected a JWT assertion because of its
+// exp or iat claim, which happens when the local clock is off.
+func isClockSkew(e *oauth2.RetrieveError) bool {
+	if e.ErrorCode != "invalid_grant" || e.Response == nil {
+		return false
+	}
+	desc := e.ErrorDescription
+	return strings.Contains(desc, "'exp'") || strings.Contains(desc, "'iat'") || strings.Contains(desc, "exp claim")
+}
+
+// assertion returns a signed JWT assertion issued at now.
+func (s *jwtSource) assertion(now time.Time) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": s.config.BoxAppSettings.AppAuth.PublicKeyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":          s.config.BoxAppSettings.ClientID,
+		"sub":          s.subId,
+		"box_sub_type": s.subType,
+		"aud":          s.box.endpoint().TokenURL,
+		"jti":          hex.EncodeToString(jti),
+		"iat":          now.Unix(),
+		"exp":          now.Add(jwtLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Data      []byte
+}
+
+type pbes2Params struct {
+	KeyDerivation pkix.AlgorithmIdentifier
+	Encryption    pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// parsePrivateKey parses the PEM encoded RSA private key of a JWT
+// config. Keys encrypted with PBES2, which is what the developer
+// console generates, are decrypted with passphrase.
+func parsePrivateKey(data, passphrase []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("No PEM encoded private key in JWT config")
+	}
+	der := block.Bytes
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(der)
+	case "ENCRYPTED PRIVATE KEY":
+		if der, err = decryptPKCS8(der, passphrase); err != nil {
+			return nil, err
+		}
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("Private key in JWT config is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// decryptPKCS8 decrypts a PBES2 encrypted PKCS #8 private key.
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, err
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, errors.New("Unsupported private key encryption, only PBES2 is supported")
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, err
+	}
+	if !params.KeyDerivation.Algorithm.Equal(oidPBKDF2) {
+		return nil, errors.New("Unsupported private key derivation, only PBKDF2 is supported")
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivation.Parameters.FullBytes, &kdf); err != nil {
+		return nil, err
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(kdf.PRF.Algorithm) == 0, kdf.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdf.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, errors.New("Unsupported private key derivation hash")
+	}
+	var keyLen int
+	switch {
+	case params.Encryption.Algorithm.Equal(oidAES128CBC):
+		keyLen = 16
+	case params.Encryption.Algorithm.Equal(oidAES192CBC):
+		keyLen = 24
+	case params.Encryption.Algorithm.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, errors.New("Unsupported private key cipher, only AES-CBC is supported")
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.Encryption.Parameters.FullBytes, &iv); err != nil {
+		return nil, err
+	}
+
+	key, err := pbkdf2.Key(prf, string(passphrase), kdf.Salt, kdf.Iterations, keyLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(info.Data) == 0 || len(info.Data)%block.BlockSize() != 0 {
+		return nil, errors.New("Malformed encrypted private key")
+	}
+	plain := make([]byte, len(info.Data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, info.Data)
+
+	// A wrong passphrase almost always shows up as a bad padding.
+	pad := int(plain[len(plain)-1])
+	if pad == 0 || pad > block.BlockSize() {
+		return nil, errors.New("Wrong passphrase for the private key")
+	}
+	for _, b := range plain[len(plain)-pad:] {
+		if int(b) != pad {
+			return nil, errors.New("Wrong passphrase for the private key")
+		}
+	}
+	return plain[:len(plain)-pad], nil
+}
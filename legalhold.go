@@ -0,0 +1,132 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// GovernanceFields are the fields of a file telling its retention
+// state, to pass to File.Get.
+var GovernanceFields = []string{"disposition_at", "expires_at", "is_externally_owned"}
+
+// IsUnderRetention checks if a retention policy applies to the file,
+// which then cannot be deleted before its DispositionAt. The file must
+// have been read with the disposition_at field, see GovernanceFields.
+func (f *File) IsUnderRetention() bool {
+	return f.DispositionAt != nil && time.Time(*f.DispositionAt).After(time.Now())
+}
+
+// LegalHoldPolicy is a legal hold policy of the enterprise.
+type LegalHoldPolicy struct {
+	Id          string   `json:"id,omitempty"`          // The id of the policy.
+	Type        string   `json:"type,omitempty"`        // Type of the object, always legal_hold_policy.
+	PolicyName  string   `json:"policy_name,omitempty"` // The name of the policy.
+	Description string   `json:"description,omitempty"` // The description of the policy.
+	Status      string   `json:"status,omitempty"`      // Whether the policy is active, applying, releasing or released.
+	CreatedAt   *BoxTime `json:"created_at,omitempty"`  // When the policy was created.
+	ReleasedAt  *BoxTime `json:"release_at,omitempty"`  // When the policy was released, if it was.
+}
+
+// FileVersionLegalHold is the hold of a legal hold policy on a version
+// of a file.
+type FileVersionLegalHold struct {
+	Id          string       `json:"id,omitempty"`           // The id of the hold.
+	Type        string       `json:"type,omitempty"`         // Type of the object, always file_version_legal_hold.
+	File        *Entity      `json:"file,omitempty"`         // The file held.
+	FileVersion *FileVersion `json:"file_version,omitempty"` // The version of the file held.
+	DeletedAt   *BoxTime     `json:"deleted_at,omitempty"`   // When the hold was released, if it was.
+}
+
+// LegalHoldPolicies returns the legal hold policies of the enterprise.
+// The box must be authenticated as an administrator.
+func (box *Box) LegalHoldPolicies(ctx context.Context, options ...RequestOption) ([]LegalHoldPolicy, error) {
+	ctx = withCall(ctx, options)
+	var policies []LegalHoldPolicy
+	params := url.Values{"usemarker": {"true"}}
+	err := box.listAll(ctx, "legal_hold_policies", params, nil, func(entries json.RawMessage) error {
+		var page []LegalHoldPolicy
+		err := box.decode(entries, &page)
+		policies = append(policies, page...)
+		return err
+	})
+	return policies, err
+}
+
+// LegalHoldPoliciesPage returns a single page of the legal hold
+// policies of the enterprise with its paging information. The box must
+// be authenticated as an administrator.
+func (box *Box) LegalHoldPoliciesPage(ctx context.Context, opts *ListOptions, options ...RequestOption) (*ListResult[LegalHoldPolicy], error) {
+	ctx = withCall(ctx, options)
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	return listPage[LegalHoldPolicy](ctx, box, "legal_hold_policies", opts.markerValues(), nil)
+}
+
+// LegalHolds returns the holds of the policy on versions of files.
+// Note that only Id is required apriori.
+func (p *LegalHoldPolicy) LegalHolds(ctx context.Context, box *Box, options ...RequestOption) ([]FileVersionLegalHold, error) {
+	ctx = withCall(ctx, options)
+	if p.Id == "" {
+		return nil, errors.New("Empty id while using LegalHolds")
+	}
+	var holds []FileVersionLegalHold
+	params := url.Values{"policy_id": {p.Id}, "usemarker": {"true"}}
+	err := box.listAll(ctx, "file_version_legal_holds", params, nil, func(entries json.RawMessage) error {
+		var page []FileVersionLegalHold
+		err := box.decode(entries, &page)
+		holds = append(holds, page...)
+		return err
+	})
+	return holds, err
+}
+
+// LegalHoldsPage returns a single page of the holds of the policy on
+// versions of files with its paging information. Note that only Id is
+// required apriori.
+func (p *LegalHoldPolicy) LegalHoldsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[FileVersionLegalHold], error) {
+	ctx = withCall(ctx, options)
+	if p.Id == "" {
+		return nil, errors.New("Empty id while using LegalHoldsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	params := opts.markerValues()
+	params.Set("policy_id", p.Id)
+	return listPage[FileVersionLegalHold](ctx, box, "file_version_legal_holds", params, nil)
+}
+
+// IsUnderLegalHold checks if a legal hold policy holds a version of the
+// file. Box has no such field on files, so the holds of every active
+// policy are listed, which takes a request per page of holds. The box
+// must be authenticated as an administrator. Note that only Id is
+// required apriori.
+func (f *File) IsUnderLegalHold(ctx context.Context, box *Box, options ...RequestOption) (bool, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return false, errors.New("Empty id while using IsUnderLegalHold")
+	}
+	policies, err := box.LegalHoldPolicies(ctx)
+	if err != nil {
+		return false, err
+	}
+	for i := range policies {
+		if policies[i].Status == "released" {
+			continue
+		}
+		holds, err := policies[i].LegalHolds(ctx, box)
+		if err != nil {
+			return false, err
+		}
+		for _, h := range holds {
+			if h.File != nil && h.File.Id == f.Id && h.DeletedAt == nil {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,173 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ListOrder is the order the entries of a listing are sorted in.
+type ListOrder struct {
+	By        string `json:"by,omitempty"`        // The field the entries are sorted by.
+	Direction string `json:"direction,omitempty"` // ASC or DESC.
+}
+
+// ListResult is a single page of a listing along with the paging
+// information Box returns with it. Offset based listings set
+// TotalCount, Limit and Offset, marker based ones set NextMarker,
+// which is empty on the last page.
+type ListResult[T any] struct {
+	Entries    []T         `json:"entries,omitempty"`     // The entries of the page.
+	TotalCount int64       `json:"total_count,omitempty"` // The number of entries of the whole listing.
+	Limit      int64       `json:"limit,omitempty"`       // The maximum number of entries of the page.
+	Offset     int64       `json:"offset,omitempty"`      // The position of the first entry of the page.
+	NextMarker string      `json:"next_marker,omitempty"` // The marker of the next page.
+	PrevMarker string      `json:"prev_marker,omitempty"` // The marker of the previous page.
+	Order      []ListOrder `json:"order,omitempty"`       // The order of the entries.
+}
+
+// ListOptions selects the page of a listing to get. The zero value
+// gets the first page with the default size.
+type ListOptions struct {
+	Limit     int      // The maximum number of entries of the page.
+	Offset    int64    // The position of the first entry, for offset based listings.
+	Marker    string   // The marker of the page, for marker based listings.
+	UseMarker bool     // Use marker based paging where the listing supports both.
+	Sort      string   // The field to sort by, among the ones the listing supports.
+	Direction string   // ASC or DESC, the order to sort in.
+	Fields    []string // The fields of the entries to get instead of the default ones.
+}
+
+// check validates the options against the sort fields the listing
+// supports, which is none when sorts is empty. Box cannot sort marker
+// based listings.
+func (o *ListOptions) check(sorts ...string) error {
+	if o == nil {
+		return nil
+	}
+	if o.Direction != "" && o.Direction != "ASC" && o.Direction != "DESC" {
+		return fmt.Errorf("Invalid direction %q, use ASC or DESC", o.Direction)
+	}
+	if o.Sort == "" && o.Direction == "" {
+		return nil
+	}
+	if len(sorts) == 0 {
+		return errors.New("This listing cannot be sorted")
+	}
+	if o.UseMarker || o.Marker != "" {
+		return errors.New("Marker based listings cannot be sorted, use offsets")
+	}
+	if o.Sort == "" {
+		return nil
+	}
+	for _, s := range sorts {
+		if o.Sort == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid sort %q, use one of %s", o.Sort, strings.Join(sorts, ", "))
+}
+
+// values returns the query parameters of the options.
+func (o *ListOptions) values() url.Values {
+	params := url.Values{}
+	if o == nil {
+		return params
+	}
+	if o.Limit > 0 {
+		params.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		params.Set("offset", strconv.FormatInt(o.Offset, 10))
+	}
+	if o.UseMarker || o.Marker != "" {
+		params.Set("usemarker", "true")
+	}
+	if o.Marker != "" {
+		params.Set("marker", o.Marker)
+	}
+	if o.Sort != "" {
+		params.Set("sort", o.Sort)
+	}
+	if o.Direction != "" {
+		params.Set("direction", o.Direction)
+	}
+	if len(o.Fields) > 0 {
+		params.Set("fields", strings.Join(o.Fields, ","))
+	}
+	return params
+}
+
+// markerValues returns the query parameters of the options for a
+// listing paged with markers only, which takes no usemarker.
+func (o *ListOptions) markerValues() url.Values {
+	params := o.values()
+	params.Del("usemarker")
+	return params
+}
+
+// listPage gets a single page of the listing at path.
+func listPage[T any](ctx context.Context, box *Box, path string, params url.Values, header http.Header) (*ListResult[T], error) {
+	body, err := box.doRequestHeader(ctx, "GET", path, &params, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	page := &ListResult[T]{}
+	if err = box.decode(body, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ItemsPage returns a single page of the items of the folder with its
+// paging information. Items can be sorted by id, name, date or size.
+// Note that only Id is required apriori.
+//
+// Deprecated: Use box.Folders.ListItems.
+func (f *Folder) ItemsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[Entity], error) {
+	return box.Folders.ListItems(ctx, f.Id, opts, options...)
+}
+
+// itemsPage gets a page of the items of f, see Folder.ItemsPage.
+func (s *folderService) itemsPage(ctx context.Context, f *Folder, opts *ListOptions, options []RequestOption) (*ListResult[Entity], error) {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using ItemsPage")
+	}
+	if err := opts.check("id", "name", "date", "size"); err != nil {
+		return nil, err
+	}
+	rawurl := fmt.Sprintf("folders/%s/items", f.Id)
+	return listPage[Entity](ctx, box, rawurl, opts.values(), nil)
+}
+
+// ItemsPage returns a single page of the items of the hub with its
+// paging information. Hub items are paged with markers only. Note that
+// only Id is required apriori.
+func (h *Hub) ItemsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[Entity], error) {
+	ctx = withCall(ctx, options)
+	if h.Id == "" {
+		return nil, errors.New("Empty id while using ItemsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	params := opts.markerValues()
+	params.Set("hub_id", h.Id)
+	return listPage[Entity](ctx, box, "hub_items", params, hubHeader())
+}
+
+// TrashPage returns a single page of the items in the trash with its
+// paging information. Items can be sorted by name, date or size.
+func (box *Box) TrashPage(ctx context.Context, opts *ListOptions, options ...RequestOption) (*ListResult[Entity], error) {
+	ctx = withCall(ctx, options)
+	if err := opts.check("name", "date", "size"); err != nil {
+		return nil, err
+	}
+	return listPage[Entity](ctx, box, "folders/trash/items", opts.values(), nil)
+}
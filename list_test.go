@@ -0,0 +1,156 @@
+package box
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// boundaries are the counts and sizes Box may send, as JSON numbers,
+// with the value they decode to unless they overflow an int64.
+var boundaries = []struct {
+	name     string
+	json     string
+	want     int64
+	overflow bool
+}{
+	{"zero", "0", 0, false},
+	{"one", "1", 1, false},
+	{"past int32", "2147483648", math.MaxInt32 + 1, false},
+	{"past uint32", "4294967296", math.MaxUint32 + 1, false},
+	{"max", "9223372036854775807", math.MaxInt64, false},
+	{"min", "-9223372036854775808", math.MinInt64, false},
+	{"one past max", "9223372036854775808", 0, true},
+	{"one past min", "-9223372036854775809", 0, true},
+}
+
+func TestDecodeBoundaries(t *testing.T) {
+	decoders := []struct {
+		name   string
+		format string
+		get    func(body []byte) (int64, error)
+	}{
+		{"ListResult.TotalCount", `{"total_count":%s}`, func(body []byte) (int64, error) {
+			var r ListResult[Entity]
+			err := json.Unmarshal(body, &r)
+			return r.TotalCount, err
+		}},
+		{"ListResult.Offset", `{"offset":%s}`, func(body []byte) (int64, error) {
+			var r ListResult[Entity]
+			err := json.Unmarshal(body, &r)
+			return r.Offset, err
+		}},
+		{"Collection.Count", `{"total_count":%s}`, func(body []byte) (int64, error) {
+			var c Collection
+			err := json.Unmarshal(body, &c)
+			return c.Count, err
+		}},
+		{"Entity.Size", `{"size":%s}`, func(body []byte) (int64, error) {
+			var e Entity
+			err := json.Unmarshal(body, &e)
+			return e.Size, err
+		}},
+		{"File.Size", `{"size":%s}`, func(body []byte) (int64, error) {
+			var f File
+			err := json.Unmarshal(body, &f)
+			return f.Size, err
+		}},
+		{"EventPage.ChunkSize", `{"chunk_size":%s}`, func(body []byte) (int64, error) {
+			var p EventPage
+			err := json.Unmarshal(body, &p)
+			return p.ChunkSize, err
+		}},
+	}
+	for _, d := range decoders {
+		for _, b := range boundaries {
+			t.Run(d.name+"/"+b.name, func(t *testing.T) {
+				got, err := d.get([]byte(fmt.Sprintf(d.format, b.json)))
+				if b.overflow {
+					if err == nil {
+						t.Fatalf("decoded %d, want an overflow error", got)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != b.want {
+					t.Errorf("decoded %d, want %d", got, b.want)
+				}
+			})
+		}
+	}
+}
+
+func TestListOptionsOffset(t *testing.T) {
+	tests := []struct {
+		offset int64
+		want   string
+	}{
+		{math.MinInt64, ""},
+		{-1, ""},
+		{0, ""},
+		{1, "1"},
+		{math.MaxInt32 + 1, "2147483648"},
+		{math.MaxInt64, strconv.FormatInt(math.MaxInt64, 10)},
+	}
+	for _, tt := range tests {
+		opts := &ListOptions{Offset: tt.offset}
+		if got := opts.values().Get("offset"); got != tt.want {
+			t.Errorf("offset %d sent as %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestStrictDecodingListing(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		strict  bool
+		unknown string
+	}{
+		{"known fields", `{"total_count":1,"entries":[{"type":"file","id":"1","name":"a"}],"limit":100,"offset":0}`, true, ""},
+		{"extra page field", `{"total_count":1,"entries":[],"limit":100,"offset":0,"extra":1}`, true, "extra"},
+		{"extra entry field", `{"total_count":1,"entries":[{"type":"file","id":"1","extra":1}]}`, true, "entries.extra"},
+		{"lenient", `{"total_count":1,"entries":[{"type":"file","id":"1","extra":1}],"extra":1}`, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestBox(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, tt.body)
+			}))
+			b.StrictDecoding = tt.strict
+			_, err := b.Folders.ListItems(context.Background(), "0", nil)
+			if tt.unknown == "" {
+				if err != nil {
+					t.Fatalf("listing failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.unknown) {
+				t.Fatalf("listing error %v, want one naming %s", err, tt.unknown)
+			}
+		})
+	}
+}
+
+func TestDecodeLogsUnknownFields(t *testing.T) {
+	var logged bytes.Buffer
+	b := NewBox()
+	b.Logger = slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	var page ListResult[Entity]
+	if err := b.decode([]byte(`{"entries":[{"id":"1","color":"red"}],"extra":1}`), &page); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logged.String(), "fields=\"[entries.color extra]\"") {
+		t.Errorf("logged %q, want the unknown fields", logged.String())
+	}
+}
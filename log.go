@@ -0,0 +1,100 @@
+package box
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxLoggedBody is the size above which bodies are not dumped to the
+// log, so that uploads and downloads never are.
+const maxLoggedBody = 64 << 10
+
+// logTransport logs the requests going through the wrapped transport
+// to the Logger of the box, retries included.
+type logTransport struct {
+	box  *Box
+	next http.RoundTripper
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.box.Logger
+	ctx := req.Context()
+	if logger == nil || !logger.Enabled(ctx, slog.LevelDebug) {
+		return t.next.RoundTrip(req)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+	}
+	if t.box.LogBodies {
+		attrs = append(attrs, slog.Any("request_header", redact(req.Header)))
+		if b := requestBody(req); b != nil {
+			attrs = append(attrs, slog.String("request_body", string(b)))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	attrs = append(attrs, slog.Duration("duration", time.Since(start)))
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+		logger.LogAttrs(ctx, slog.LevelDebug, "box request", attrs...)
+		return resp, err
+	}
+
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	if id := resp.Header.Get("Box-Request-Id"); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	if t.box.LogBodies && resp.ContentLength >= 0 && resp.ContentLength <= maxLoggedBody {
+		b, rerr := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+		if rerr != nil {
+			return nil, rerr
+		}
+		attrs = append(attrs, slog.String("response_body", string(b)))
+	}
+	logger.LogAttrs(ctx, slog.LevelDebug, "box request", attrs...)
+	return resp, nil
+}
+
+// requestBody returns a copy of the body of the request if it is small
+// enough to be logged and can be read again.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil || req.ContentLength <= 0 || req.ContentLength > maxLoggedBody {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	b, err := ioutil.ReadAll(io.LimitReader(body, maxLoggedBody))
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// redact returns a copy of the header safe to log, without the
+// credentials it carries: the token and the password of the shared
+// link of the BoxApi header, see WithSharedLink.
+func redact(h http.Header) http.Header {
+	h = h.Clone()
+	if _, ok := h["Authorization"]; ok {
+		h.Set("Authorization", "REDACTED")
+	}
+	if v := h.Get("BoxApi"); v != "" {
+		if i := strings.Index(v, "shared_link_password="); i >= 0 {
+			h.Set("BoxApi", v[:i]+"shared_link_password=REDACTED")
+		}
+	}
+	return h
+}
@@ -0,0 +1,50 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SetMetadata sets the given fields of the instance of the metadata
+// template, e.g. scope enterprise and template projectInfo, on the
+// folder. The instance is created if the folder has none yet, else its
+// fields are replaced, leaving the other fields as they are. Note that
+// only Id is required apriori.
+func (f *Folder) SetMetadata(ctx context.Context, box *Box, scope, template string, values map[string]interface{}, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using SetMetadata")
+	}
+	if scope == "" || template == "" {
+		return errors.New("Empty scope or template while using SetMetadata")
+	}
+
+	rawurl := fmt.Sprintf("folders/%s/metadata/%s/%s", f.Id, scope, template)
+	reqBody, _ := json.Marshal(values)
+	header := http.Header{"Content-Type": {"application/json"}}
+	_, err := box.doRequestHeader(ctx, "POST", rawurl, nil, header, reqBody)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		return err
+	}
+
+	// The instance exists, patch its fields.
+	type operation struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}
+	ops := make([]operation, 0, len(values))
+	for k, v := range values {
+		path := "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+		ops = append(ops, operation{"add", path, v})
+	}
+	reqBody, _ = json.Marshal(ops)
+	header = http.Header{"Content-Type": {"application/json-patch+json"}}
+	_, err = box.doRequestHeader(ctx, "PUT", rawurl, nil, header, reqBody)
+	return err
+}
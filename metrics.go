@@ -0,0 +1,52 @@
+package box
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestMetrics describes a request sent by the box.
+type RequestMetrics struct {
+	Method      string        // The http method of the request.
+	Endpoint    string        // The api path with ids replaced, e.g. "folders/{id}/items", or "content" outside of the api.
+	Status      int           // The status of the response, zero if none was received.
+	Latency     time.Duration // The time until the response headers were received.
+	RateLimited bool          // Whether Box answered 429 Too Many Requests.
+	RetryAfter  time.Duration // How long Box asked to wait, if it did.
+}
+
+// MetricsRecorder gets the metrics of every request sent by the box,
+// retries, uploads and downloads included, e.g. to export them to
+// Prometheus. It must be safe for concurrent use.
+type MetricsRecorder interface {
+	RecordRequest(m RequestMetrics)
+}
+
+// metricsTransport reports the requests going through the wrapped
+// transport to the Metrics of the box.
+type metricsTransport struct {
+	box  *Box
+	next http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.box.Metrics == nil {
+		return t.next.RoundTrip(req)
+	}
+	m := RequestMetrics{Method: req.Method, Endpoint: "content"}
+	if p, ok := t.box.apiPath(req.URL); ok {
+		m.Endpoint, _ = operation(p)
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	m.Latency = time.Since(start)
+	if resp != nil {
+		m.Status = resp.StatusCode
+		m.RateLimited = resp.StatusCode == TOO_MANY_REQUESTS.StatusCode
+		if m.RateLimited {
+			m.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+	}
+	t.box.Metrics.RecordRequest(m)
+	return resp, err
+}
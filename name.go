@@ -0,0 +1,93 @@
+package box
+
+import (
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxNameLength is the maximum length of item names in Box.
+const maxNameLength = 255
+
+// NameError is returned when an item name would be rejected by Box.
+type NameError struct {
+	Name   string
+	Reason string
+}
+
+func (e *NameError) Error() string {
+	return fmt.Sprintf("Invalid name %q: %v", e.Name, e.Reason)
+}
+
+// ValidateName checks that Box accepts name as the name of a file or
+// folder: it must not be empty, "." or "..", contain slashes,
+// backslashes or non printable characters, start or end with spaces, or
+// be longer than 255 characters.
+func ValidateName(name string) error {
+	switch {
+	case name == "":
+		return &NameError{name, "empty"}
+	case name == "." || name == "..":
+		return &NameError{name, "reserved"}
+	case strings.ContainsAny(name, `/\`):
+		return &NameError{name, "contains a slash or a backslash"}
+	case strings.TrimSpace(name) != name:
+		return &NameError{name, "starts or ends with spaces"}
+	case utf8.RuneCountInString(name) > maxNameLength:
+		return &NameError{name, "longer than 255 characters"}
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) && r != ' ' {
+			return &NameError{name, "contains non printable characters"}
+		}
+	}
+	return nil
+}
+
+// SanitizeName rewrites name so that Box accepts it: slashes and
+// backslashes become underscores, non printable characters are dropped,
+// surrounding spaces are trimmed and the name is cut to 255
+// characters. Names left empty or reserved become "_".
+func SanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == '\\':
+			return '_'
+		case !unicode.IsPrint(r) && r != ' ':
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxNameLength {
+		name = strings.TrimSpace(string([]rune(name)[:maxNameLength]))
+	}
+	if name == "" || name == "." || name == ".." {
+		name = "_"
+	}
+	return name
+}
+
+// checkName normalizes and sanitizes name if the box is set to, then
+// validates it.
+func (box *Box) checkName(name string) (string, error) {
+	if box.NormalizeNames {
+		name = norm.NFC.String(name)
+	}
+	if box.SanitizeNames {
+		name = SanitizeName(name)
+	}
+	return name, ValidateName(name)
+}
+
+// sameName reports whether a and b name the same item. With
+// NormalizeNames, names differing only by their unicode normalization
+// are the same.
+func (box *Box) sameName(a, b string) bool {
+	if box.NormalizeNames {
+		return norm.NFC.String(a) == norm.NFC.String(b)
+	}
+	return a == b
+}
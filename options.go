@@ -0,0 +1,203 @@
+package box
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestOption changes a single call of an api method, such as the
+// fields it gets or the user it acts as. Every api method accepts them
+// after its own arguments:
+//
+//	err := file.Get(ctx, b, box.WithFields("name", "size"), box.WithAsUser(userId))
+//
+// The options apply to the requests of the method only. The other
+// methods it relies on, e.g. the downloads of DownloadTree, only get
+// the user of WithAsUser and the deadline of WithTimeout.
+type RequestOption func(*callOptions)
+
+// callOptions are the options of a call, carried by its context.
+type callOptions struct {
+	fields   string
+	limit    int
+	ifMatch  string
+	asUser   *string
+	timeout  time.Duration
+	deadline time.Time
+
+	header     http.Header
+	sharedLink *sharedLink
+	responses  []*ResponseInfo
+
+	uploadProgress   ProgressFunc
+	downloadProgress ProgressFunc
+	contentType      string
+}
+
+type callKey struct{}
+
+// WithAsUser makes the call act on behalf of the given managed user,
+// like SetAsUser but for this call only. An empty id stops the
+// impersonation set on the box.
+func WithAsUser(userId string) RequestOption {
+	return func(o *callOptions) { o.asUser = &userId }
+}
+
+// WithFields makes the call get only the given fields of the objects,
+// unless the method asks for fields itself.
+func WithFields(fields ...string) RequestOption {
+	return func(o *callOptions) { o.fields = strings.Join(fields, ",") }
+}
+
+// WithLimit makes the listings of the call get pages of up to limit
+// entries, unless the method sets the page size itself.
+func WithLimit(limit int) RequestOption {
+	return func(o *callOptions) { o.limit = limit }
+}
+
+// WithIfMatch makes the call fail with PRECONDITION_FAILED when the
+// item no longer has the given etag.
+func WithIfMatch(etag string) RequestOption {
+	return func(o *callOptions) { o.ifMatch = etag }
+}
+
+// WithTimeout makes the call fail once it took longer than d, retries
+// and waits included. It cannot extend the deadline of ctx.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *callOptions) { o.timeout = d }
+}
+
+// withCall returns the context of a call of an api method with the
+// given options. The options of an enclosing call are dropped, but for
+// its user and deadline.
+func withCall(ctx context.Context, options []RequestOption) context.Context {
+	o := &callOptions{}
+	if parent := callOpts(ctx); parent != nil {
+		o.asUser, o.deadline = parent.asUser, parent.deadline
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	if o.timeout > 0 {
+		if d := time.Now().Add(o.timeout); o.deadline.IsZero() || d.Before(o.deadline) {
+			o.deadline = d
+		}
+	}
+	return context.WithValue(ctx, callKey{}, o)
+}
+
+// callOpts returns the options of the call of ctx, or nil.
+func callOpts(ctx context.Context) *callOptions {
+	o, _ := ctx.Value(callKey{}).(*callOptions)
+	return o
+}
+
+// callFields returns the fields set by the options of the call.
+func callFields(ctx context.Context) []string {
+	if o := callOpts(ctx); o != nil && o.fields != "" {
+		return strings.Split(o.fields, ",")
+	}
+	return nil
+}
+
+// callParams returns params with the fields and limit of the call
+// added where params does not set them.
+func callParams(ctx context.Context, params *url.Values) *url.Values {
+	o := callOpts(ctx)
+	if o == nil || o.fields == "" && o.limit <= 0 {
+		return params
+	}
+	p := url.Values{}
+	if params != nil {
+		for k, v := range *params {
+			p[k] = v
+		}
+	}
+	if o.fields != "" && p.Get("fields") == "" {
+		p.Set("fields", o.fields)
+	}
+	if o.limit > 0 && p.Get("limit") == "" {
+		p.Set("limit", strconv.Itoa(o.limit))
+	}
+	return &p
+}
+
+// callLimit returns the page size set by the call, or def.
+func callLimit(ctx context.Context, def int) int {
+	if o := callOpts(ctx); o != nil && o.limit > 0 {
+		return o.limit
+	}
+	return def
+}
+
+// setCallHeaders sets the headers of the options of the call of the
+// request.
+func setCallHeaders(r *http.Request, o *callOptions) {
+	if o.sharedLink != nil {
+		setSharedLinkHeader(r, o.sharedLink)
+	}
+	for k, v := range o.header {
+		if k == "Authorization" {
+			continue
+		}
+		r.Header[k] = append([]string(nil), v...)
+	}
+	if o.asUser != nil {
+		if *o.asUser == "" {
+			r.Header.Del("As-User")
+		} else {
+			r.Header.Set("As-User", *o.asUser)
+		}
+	}
+	if o.ifMatch != "" {
+		r.Header.Set("If-Match", o.ifMatch)
+	}
+}
+
+// callDeadline returns the context of the request bounded by the
+// deadline of its call, if any, and the function releasing it.
+func callDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o := callOpts(ctx); o != nil && !o.deadline.IsZero() {
+		return context.WithDeadline(ctx, o.deadline)
+	}
+	return ctx, func() {}
+}
+
+// cancelBody releases the context of a request once its response body
+// is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// deadlineTransport bounds the requests going through the wrapped
+// transport, rate limiting and reading the response included, by the
+// deadline of their call, see WithTimeout.
+type deadlineTransport struct {
+	next http.RoundTripper
+}
+
+func (t *deadlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := callDeadline(req.Context())
+	if ctx == req.Context() {
+		return t.next.RoundTrip(req)
+	}
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{resp.Body, cancel}
+	return resp, nil
+}
@@ -0,0 +1,43 @@
+//go:build otel
+
+package box
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Built with the otel tag, every api call, upload and download is
+// traced as an OpenTelemetry span of the global tracer provider.
+func init() {
+	startSpan = otelSpan
+}
+
+const tracerName = "github.com/satvikc/go-box"
+
+func otelSpan(ctx context.Context, method, path string) (context.Context, func(status, retries int, err error)) {
+	op, id := operation(path)
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", method),
+		attribute.String("box.operation", op),
+	}
+	if id != "" {
+		attrs = append(attrs, attribute.String("box.item_id", id))
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "box "+method+" "+op,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return ctx, func(status, retries int, err error) {
+		if status != 0 {
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+		}
+		span.SetAttributes(attribute.Int("box.retries", retries))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,69 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/oauth2"
+	"net/url"
+)
+
+// PingFailure tells why a Ping failed.
+type PingFailure int
+
+const (
+	PingAuth    PingFailure = iota + 1 // The token is missing, invalid or cannot be refreshed.
+	PingNetwork                        // Box could not be reached.
+	PingService                        // Box answered with an error.
+)
+
+func (f PingFailure) String() string {
+	switch f {
+	case PingAuth:
+		return "auth"
+	case PingNetwork:
+		return "network"
+	case PingService:
+		return "service"
+	}
+	return "unknown"
+}
+
+// PingError is returned by Ping with the category of the failure.
+type PingError struct {
+	Kind PingFailure
+	Err  error
+}
+
+func (e *PingError) Error() string {
+	return fmt.Sprintf("Ping failed (%v): %v", e.Kind, e.Err)
+}
+
+func (e *PingError) Unwrap() error { return e.Err }
+
+// Ping checks that Box can be reached and accepts the token of the box
+// with a lightweight authenticated request, e.g. for readiness probes.
+// Failures are returned as PingError telling auth, network and service
+// failures apart.
+func (box *Box) Ping(ctx context.Context, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	_, err := box.doRequest(ctx, "GET", "users/me", &url.Values{"fields": {"id"}}, nil)
+	if err == nil {
+		return nil
+	}
+	var boxErr *BoxError
+	var authErr *AuthError
+	var retrieveErr *oauth2.RetrieveError
+	switch {
+	case errors.As(err, &authErr):
+		return &PingError{PingAuth, err}
+	case errors.As(err, &boxErr):
+		if boxErr.StatusCode == UNAUTHORIZED.StatusCode || boxErr.StatusCode == FORBIDDEN.StatusCode {
+			return &PingError{PingAuth, err}
+		}
+		return &PingError{PingService, err}
+	case errors.As(err, &retrieveErr):
+		return &PingError{PingAuth, err}
+	}
+	return &PingError{PingNetwork, err}
+}
@@ -0,0 +1,52 @@
+package box
+
+import "time"
+
+// Profile is a bundle of client settings suited to a kind of workload.
+type Profile struct {
+	Timeout         time.Duration // See Box.Timeout.
+	TransferTimeout time.Duration // See Box.TransferTimeout.
+	MaxRetries      int           // See Box.MaxRetries.
+	RetryBackoff    time.Duration // See Box.RetryBackoff.
+	RetryUploadSize int64         // See Box.RetryUploadSize.
+
+	RequestsPerSecond float64 // See Box.RequestsPerSecond.
+	Burst             int     // See Box.Burst.
+}
+
+var (
+	// ProfileInteractive fails fast for users waiting on the result:
+	// short timeouts and a single quick retry.
+	ProfileInteractive = Profile{
+		Timeout:         10 * time.Second,
+		TransferTimeout: 5 * time.Minute,
+		MaxRetries:      1,
+		RetryBackoff:    500 * time.Millisecond,
+		RetryUploadSize: 8 << 20,
+	}
+
+	// ProfileBatch favors completing over latency for unattended jobs:
+	// long timeouts, many patient retries, larger retryable uploads and
+	// a rate limit below the one of Box.
+	ProfileBatch = Profile{
+		Timeout:           2 * time.Minute,
+		TransferTimeout:   0,
+		MaxRetries:        8,
+		RetryBackoff:      2 * time.Second,
+		RetryUploadSize:   32 << 20,
+		RequestsPerSecond: 12,
+		Burst:             12,
+	}
+)
+
+// UseProfile applies the settings of the profile to the box. Like the
+// fields it sets, it must be called before the first request.
+func (box *Box) UseProfile(p Profile) {
+	box.Timeout = p.Timeout
+	box.TransferTimeout = p.TransferTimeout
+	box.MaxRetries = p.MaxRetries
+	box.RetryBackoff = p.RetryBackoff
+	box.RetryUploadSize = p.RetryUploadSize
+	box.RequestsPerSecond = p.RequestsPerSecond
+	box.Burst = p.Burst
+}
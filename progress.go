@@ -0,0 +1,61 @@
+package box
+
+import (
+	"context"
+	"io"
+)
+
+// ProgressFunc is told the number of bytes transferred so far and the
+// total, -1 if unknown. It is not called concurrently but may be
+// called from another goroutine than the one transferring.
+type ProgressFunc func(done, total int64)
+
+// WithUploadProgress makes the upload of the call, chunked or not,
+// report its progress to fn, e.g. to show a progress bar. A retried
+// upload starts again from zero.
+func WithUploadProgress(fn ProgressFunc) RequestOption {
+	return func(o *callOptions) { o.uploadProgress = fn }
+}
+
+// uploadProgress returns the ProgressFunc of the upload of the call of
+// ctx, or nil.
+func uploadProgress(ctx context.Context) ProgressFunc {
+	if o := callOpts(ctx); o != nil {
+		return o.uploadProgress
+	}
+	return nil
+}
+
+// progressReader reports the bytes read through it to fn.
+type progressReader struct {
+	r     io.Reader
+	n     int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.n += int64(n)
+		p.fn(p.n, p.total)
+	}
+	return n, err
+}
+
+// WithDownloadProgress makes the download of the call report its
+// progress to fn, the total being the size of the file when Box sends
+// it. A resumed download carries on from the bytes already written, so
+// a progress stuck for long tells a stalled transfer.
+func WithDownloadProgress(fn ProgressFunc) RequestOption {
+	return func(o *callOptions) { o.downloadProgress = fn }
+}
+
+// downloadProgress returns the ProgressFunc of the download of the call
+// of ctx, or nil.
+func downloadProgress(ctx context.Context) ProgressFunc {
+	if o := callOpts(ctx); o != nil {
+		return o.downloadProgress
+	}
+	return nil
+}
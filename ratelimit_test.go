@@ -0,0 +1,98 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSpacing(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     float64
+		burst    int
+		waits    int
+		min, max time.Duration
+	}{
+		{"burst only", 10, 5, 5, 0, 50 * time.Millisecond},
+		{"no burst", 50, 0, 4, 60 * time.Millisecond, 500 * time.Millisecond},
+		{"past burst", 50, 2, 5, 60 * time.Millisecond, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter(tt.rate, tt.burst)
+			start := time.Now()
+			for i := 0; i < tt.waits; i++ {
+				if err := l.wait(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if d := time.Since(start); d < tt.min || d > tt.max {
+				t.Errorf("%d waits took %v, want between %v and %v", tt.waits, d, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRateLimitedRequests(t *testing.T) {
+	var mu sync.Mutex
+	var sent []time.Time
+	b := newTestBox(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, time.Now())
+		mu.Unlock()
+		io.WriteString(w, `{}`)
+	}))
+	b.RequestsPerSecond = 50
+	b.Burst = 1
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := b.doRequest(context.Background(), "GET", "items", nil, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 20ms apart, less the network and scheduling noise.
+	for i := 1; i < len(sent); i++ {
+		if gap := sent[i].Sub(sent[i-1]); gap < 10*time.Millisecond {
+			t.Errorf("request %d sent %v after the previous one, want about 20ms", i, gap)
+		}
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := newRateLimiter(1, 1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next token comes in a second, the waiter must not wait for it.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	err := l.wait(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("wait returned %v, want it cancelled", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("cancelled wait returned after %v", d)
+	}
+
+	// The token reserved by the cancelled waiter is given back.
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("bucket holds %.2f tokens after the cancellation, want the reservation given back", tokens)
+	}
+}
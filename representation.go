@@ -0,0 +1,228 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// States of a representation.
+const (
+	REP_SUCCESS  = "success"  // The representation is ready.
+	REP_VIEWABLE = "viewable" // Enough of the representation is ready to start viewing it.
+	REP_PENDING  = "pending"  // The representation is being generated.
+	REP_NONE     = "none"     // The representation was not generated yet, reading its info starts it.
+	REP_ERROR    = "error"    // The representation cannot be generated.
+)
+
+// Representation is a rendition of a file generated by Box, such as a
+// pdf, the extracted text or a jpg thumbnail.
+type Representation struct {
+	Representation string                    `json:"representation,omitempty"` // The kind of representation, e.g. pdf, extracted_text or jpg.
+	Properties     *RepresentationProperties `json:"properties,omitempty"`     // Details such as the dimensions of images.
+	Info           *RepresentationInfo       `json:"info,omitempty"`           // Where to read the state of the representation.
+	Status         *RepresentationStatus     `json:"status,omitempty"`         // The state of the generation.
+	Content        *RepresentationContent    `json:"content,omitempty"`        // Where to download the representation.
+}
+
+// RepresentationProperties holds the details of a representation. Box
+// sends the flags as strings.
+type RepresentationProperties struct {
+	Dimensions string `json:"dimensions,omitempty"` // The size of images, e.g. 1024x1024.
+	Paged      string `json:"paged,omitempty"`      // Whether there is an asset per page, "true" or "false".
+	Thumb      string `json:"thumb,omitempty"`      // Whether the image is meant as a thumbnail, "true" or "false".
+}
+
+// RepresentationInfo tells where to read the state of a representation.
+type RepresentationInfo struct {
+	Url string `json:"url,omitempty"` // The url of the representation info, reading it starts the generation.
+}
+
+// RepresentationStatus is the state of the generation of a
+// representation.
+type RepresentationStatus struct {
+	State string `json:"state,omitempty"` // One of the REP states.
+}
+
+// RepresentationContent tells where to download a representation.
+type RepresentationContent struct {
+	UrlTemplate string `json:"url_template,omitempty"` // The url of the content, with an {+asset_path} placeholder.
+}
+
+// Size returns the width and height of an image representation, read
+// from its dimensions. ok is false for other representations.
+func (p *RepresentationProperties) Size() (width, height int, ok bool) {
+	if p == nil {
+		return 0, 0, false
+	}
+	w, h, found := strings.Cut(p.Dimensions, "x")
+	if !found {
+		return 0, 0, false
+	}
+	width, werr := strconv.Atoi(w)
+	height, herr := strconv.Atoi(h)
+	if werr != nil || herr != nil {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// IsPaged checks if the representation has an asset per page, e.g.
+// "1.png", instead of a single one.
+func (p *RepresentationProperties) IsPaged() bool {
+	return p != nil && p.Paged == "true"
+}
+
+// BestThumbnail returns the smallest jpg or png representation at least
+// width by height, or else the largest one, to scale it down as little
+// as possible. It returns nil when there is no image representation.
+func BestThumbnail(reps []Representation, width, height int) *Representation {
+	var best *Representation
+	var bestW, bestH int
+	for i := range reps {
+		r := &reps[i]
+		if r.Representation != "jpg" && r.Representation != "png" {
+			continue
+		}
+		w, h, ok := r.Properties.Size()
+		if !ok {
+			continue
+		}
+		fits := w >= width && h >= height
+		bestFits := bestW >= width && bestH >= height
+		switch {
+		case best == nil,
+			fits && !bestFits,
+			fits && bestFits && w*h < bestW*bestH,
+			!fits && !bestFits && w*h > bestW*bestH:
+			best, bestW, bestH = r, w, h
+		}
+	}
+	return best
+}
+
+// Representations returns the representations of the file matching the
+// given hints, e.g. "[pdf][extracted_text][jpg?dimensions=320x320]",
+// all the available ones if empty. Note that only Id is required
+// apriori.
+func (f *File) Representations(ctx context.Context, box *Box, hints string, options ...RequestOption) ([]Representation, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using Representations")
+	}
+	var header http.Header
+	if hints != "" {
+		header = http.Header{"X-Rep-Hints": {hints}}
+	}
+	rawurl := fmt.Sprintf("files/%s", f.Id)
+	body, err := box.doRequestHeader(ctx, "GET", rawurl, fieldsParams([]string{"representations"}), header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Representations *struct {
+			Entries []Representation `json:"entries,omitempty"`
+		} `json:"representations,omitempty"`
+	}
+	if err = box.decode(body, &file); err != nil {
+		return nil, err
+	}
+	if file.Representations == nil {
+		return nil, nil
+	}
+	return file.Representations.Entries, nil
+}
+
+// State returns the state of the representation, one of the REP
+// states.
+func (r *Representation) State() string {
+	if r.Status == nil {
+		return ""
+	}
+	return r.Status.State
+}
+
+// Wait waits until the representation is generated, polling its info
+// as Box asks, and updates it. It fails when Box cannot generate it.
+// Bound it with the deadline of ctx.
+func (r *Representation) Wait(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	for {
+		switch r.State() {
+		case REP_SUCCESS:
+			return nil
+		case REP_ERROR:
+			return fmt.Errorf("Box cannot generate the %s representation", r.Representation)
+		}
+		if r.Info == nil || r.Info.Url == "" {
+			return errors.New("Empty info url while using Wait")
+		}
+		body, retryAfter, err := box.getURL(ctx, r.Info.Url)
+		if err != nil {
+			return err
+		}
+		if err = box.decode(body, r); err != nil {
+			return err
+		}
+		if r.State() == REP_SUCCESS || r.State() == REP_ERROR {
+			continue
+		}
+		if err = sleep(ctx, box.pollDelay(retryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// Download waits for the representation to be generated and writes the
+// given asset of it to w: "" for single file representations such as
+// pdf or extracted_text, or e.g. "1.png" for the pages of paged ones.
+func (r *Representation) Download(ctx context.Context, box *Box, asset string, w io.Writer, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if err := r.Wait(ctx, box); err != nil {
+		return err
+	}
+	if r.Content == nil || r.Content.UrlTemplate == "" {
+		return errors.New("Empty content url while using Download")
+	}
+	rawurl := strings.Replace(r.Content.UrlTemplate, "{+asset_path}", asset, 1)
+
+	request, err := http.NewRequestWithContext(ctx, "GET", rawurl, nil)
+	if err != nil {
+		return err
+	}
+	response, err := box.contentClient().Do(request)
+	if err != nil {
+		return err
+	}
+	defer closeResponse(response)
+
+	if response.StatusCode != http.StatusOK {
+		// Read the error details sent by Box.
+		if _, err = box.getResponse(response); err == nil {
+			err = toError(response.StatusCode)
+		}
+		return err
+	}
+	_, err = io.Copy(w, response.Body)
+	return err
+}
+
+// getURL gets the absolute url given by Box, such as a representation
+// info. It also returns the Retry-After of the response.
+func (box *Box) getURL(ctx context.Context, rawurl string) ([]byte, string, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", rawurl, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	response, err := box.client().Do(request)
+	if err != nil {
+		return nil, "", err
+	}
+	defer closeResponse(response)
+	body, err := box.getResponse(response)
+	return body, response.Header.Get("Retry-After"), err
+}
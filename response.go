@@ -0,0 +1,84 @@
+package box
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ResponseInfo receives the details of the last response to the
+// requests of a call given WithResponseInfo, e.g. to correlate the call
+// with Box support or watch the rate limits. It is safe to read once
+// the call returned.
+type ResponseInfo struct {
+	mu         sync.Mutex
+	statusCode int
+	requestId  string
+	retryAfter time.Duration
+	header     http.Header
+}
+
+// WithResponseInfo makes the requests of the call record their
+// responses into info, retries included, the last one winning.
+func WithResponseInfo(info *ResponseInfo) RequestOption {
+	return func(o *callOptions) { o.responses = append(o.responses, info) }
+}
+
+// recordingResponses returns the context of the call of ctx also
+// recording its responses into info, keeping the options of the call.
+func recordingResponses(ctx context.Context, info *ResponseInfo) context.Context {
+	o := &callOptions{}
+	if parent := callOpts(ctx); parent != nil {
+		*o = *parent
+	}
+	o.responses = append(o.responses[:len(o.responses):len(o.responses)], info)
+	return context.WithValue(ctx, callKey{}, o)
+}
+
+// StatusCode returns the status of the last response.
+func (i *ResponseInfo) StatusCode() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.statusCode
+}
+
+// RequestId returns the Box-Request-Id of the last response, to report
+// to Box support.
+func (i *ResponseInfo) RequestId() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.requestId
+}
+
+// RetryAfter returns how long Box asked to wait in the last response,
+// if it did.
+func (i *ResponseInfo) RetryAfter() time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.retryAfter
+}
+
+// Header returns the headers of the last response.
+func (i *ResponseInfo) Header() http.Header {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.header
+}
+
+// recordResponse records the response into the ResponseInfo of the
+// options of the call.
+func recordResponse(o *callOptions, resp *http.Response) {
+	if len(o.responses) == 0 {
+		return
+	}
+	after, _ := parseRetryAfter(resp.Header.Get("Retry-After"))
+	for _, info := range o.responses {
+		info.mu.Lock()
+		info.statusCode = resp.StatusCode
+		info.requestId = resp.Header.Get("Box-Request-Id")
+		info.retryAfter = after
+		info.header = resp.Header.Clone()
+		info.mu.Unlock()
+	}
+}
@@ -0,0 +1,98 @@
+package box
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxBackoff caps the wait between two attempts of a request.
+const maxBackoff = time.Minute
+
+// retryable checks if a request with the given method answered with
+// the given status may be sent again. Rate limited requests were not
+// processed and are always retried, but a server error may come after
+// the request took effect, so only idempotent requests are retried,
+// lest e.g. a folder gets copied twice.
+func retryable(method string, status int) bool {
+	if status == TOO_MANY_REQUESTS.StatusCode {
+		return true
+	}
+	return idempotent(method) && serverError(status)
+}
+
+// serverError checks if the status is a transient failure of Box.
+func serverError(status int) bool {
+	switch status {
+	case SERVER_ERROR.StatusCode, UNAVAILABLE.StatusCode,
+		http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before the next attempt, preferring
+// the Retry-After header of the failed response over the exponential
+// backoff of the box.
+func (box *Box) backoff(attempt int, retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		return d
+	}
+	d := box.RetryBackoff << uint(attempt)
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	// Add up to 50% jitter so that concurrent clients do not retry in
+	// lockstep.
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// pollDelay returns how long to wait before polling an accepted
+// operation again, preferring the Retry-After header of the answer.
+func (box *Box) pollDelay(retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter); ok {
+		return d
+	}
+	if box.RetryBackoff > 0 {
+		return box.RetryBackoff
+	}
+	return time.Second
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a
+// number of seconds or a date.
+func parseRetryAfter(retryAfter string) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(retryAfter); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(retryAfter); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done or past the deadline of its
+// call.
+func sleep(ctx context.Context, d time.Duration) error {
+	ctx, cancel := callDeadline(ctx)
+	defer cancel()
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
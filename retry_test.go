@@ -0,0 +1,154 @@
+package box
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		method string
+		status int
+		want   bool
+	}{
+		{"GET", 429, true},
+		{"POST", 429, true},
+		{"GET", 500, true},
+		{"HEAD", 502, true},
+		{"PUT", 503, true},
+		{"DELETE", 504, true},
+		{"OPTIONS", 503, true},
+		{"POST", 500, false},
+		{"POST", 503, false},
+		{"GET", 501, false},
+		{"GET", 400, false},
+		{"GET", 404, false},
+		{"PUT", 409, false},
+		{"GET", 200, false},
+	}
+	for _, tt := range tests {
+		if got := retryable(tt.method, tt.status); got != tt.want {
+			t.Errorf("retryable(%s, %d) = %v, want %v", tt.method, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       time.Duration
+		attempt    int
+		retryAfter string
+		min, max   time.Duration
+	}{
+		{"first attempt", 100 * time.Millisecond, 0, "", 100 * time.Millisecond, 150 * time.Millisecond},
+		{"doubled", 100 * time.Millisecond, 3, "", 800 * time.Millisecond, 1200 * time.Millisecond},
+		{"capped", time.Second, 10, "", maxBackoff, maxBackoff * 3 / 2},
+		{"overflow", time.Second, 70, "", maxBackoff, maxBackoff * 3 / 2},
+		{"retry after seconds", time.Hour, 5, "2", 2 * time.Second, 2 * time.Second},
+		{"retry after zero", time.Hour, 5, "0", 0, 0},
+		{"retry after past date", time.Hour, 0, "Mon, 02 Jan 2006 15:04:05 GMT", 0, 0},
+		{"retry after invalid", 100 * time.Millisecond, 1, "soon", 200 * time.Millisecond, 300 * time.Millisecond},
+		{"retry after negative", 100 * time.Millisecond, 0, "-1", 100 * time.Millisecond, 150 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Box{RetryBackoff: tt.base}
+			for i := 0; i < 20; i++ {
+				if d := b.backoff(tt.attempt, tt.retryAfter); d < tt.min || d > tt.max {
+					t.Fatalf("waits %v, want between %v and %v", d, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+// failingAPI answers with status until it was requested fails times,
+// then with an empty object.
+type failingAPI struct {
+	status     int
+	fails      int32
+	retryAfter string
+	requests   int32
+}
+
+func (a *failingAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if atomic.AddInt32(&a.requests, 1) <= a.fails {
+		if a.retryAfter != "" {
+			w.Header().Set("Retry-After", a.retryAfter)
+		}
+		w.WriteHeader(a.status)
+		io.WriteString(w, `{"type":"error"}`)
+		return
+	}
+	io.WriteString(w, `{}`)
+}
+
+func TestRequestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		fails      int32
+		maxRetries int
+		backoff    time.Duration
+		retryAfter string
+		requests   int32
+		ok         bool
+	}{
+		{"rate limited get", "GET", 429, 2, 3, time.Millisecond, "", 3, true},
+		{"rate limited post", "POST", 429, 2, 3, time.Millisecond, "", 3, true},
+		{"server error get", "GET", 503, 3, 3, time.Millisecond, "", 4, true},
+		{"server error put", "PUT", 500, 1, 3, time.Millisecond, "", 2, true},
+		{"server error delete", "DELETE", 502, 1, 3, time.Millisecond, "", 2, true},
+		{"server error post", "POST", 503, 1, 3, time.Millisecond, "", 1, false},
+		{"client error", "GET", 400, 1, 3, time.Millisecond, "", 1, false},
+		{"max retries", "GET", 503, 5, 3, time.Millisecond, "", 4, false},
+		{"no retries", "GET", 429, 1, 0, time.Millisecond, "", 1, false},
+		// The backoff would outlast the test, Retry-After must win.
+		{"retry after", "GET", 429, 2, 3, time.Hour, "0", 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &failingAPI{status: tt.status, fails: tt.fails, retryAfter: tt.retryAfter}
+			b := newTestBox(t, api)
+			b.MaxRetries = tt.maxRetries
+			b.RetryBackoff = tt.backoff
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			var body []byte
+			if tt.method == "POST" || tt.method == "PUT" {
+				body = []byte(`{}`)
+			}
+			_, err := b.doRequest(ctx, tt.method, "items", nil, body)
+			if got := atomic.LoadInt32(&api.requests); got != tt.requests {
+				t.Errorf("sent %d requests, want %d", got, tt.requests)
+			}
+			if tt.ok && err != nil {
+				t.Errorf("request failed: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("request succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestRetryWaitsForBackoff(t *testing.T) {
+	api := &failingAPI{status: 503, fails: 2}
+	b := newTestBox(t, api)
+	b.RetryBackoff = 20 * time.Millisecond
+	start := time.Now()
+	if _, err := b.doRequest(context.Background(), "GET", "items", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	// 20ms then 40ms, jitter only adds to them.
+	if d := time.Since(start); d < 60*time.Millisecond {
+		t.Errorf("retried after %v, want at least 60ms of backoff", d)
+	}
+}
@@ -0,0 +1,56 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// SearchFilter narrows a search down. Empty fields do not filter.
+type SearchFilter struct {
+	Type            string   // file, folder or web_link.
+	AncestorFolders []string // The ids of the folders to search in.
+	FileExtensions  []string // The extensions of the files, without the dot.
+	ContentTypes    []string // Where to look for the query: name, description, file_content, comments or tag.
+}
+
+// values adds the query parameters of the filter to params.
+func (f *SearchFilter) values(params url.Values) {
+	if f == nil {
+		return
+	}
+	if f.Type != "" {
+		params.Set("type", f.Type)
+	}
+	if len(f.AncestorFolders) > 0 {
+		params.Set("ancestor_folder_ids", strings.Join(f.AncestorFolders, ","))
+	}
+	if len(f.FileExtensions) > 0 {
+		params.Set("file_extensions", strings.Join(f.FileExtensions, ","))
+	}
+	if len(f.ContentTypes) > 0 {
+		params.Set("content_types", strings.Join(f.ContentTypes, ","))
+	}
+}
+
+// SearchPage returns a single page of the items matching the query,
+// and the filter if any, with its paging information. Search is paged
+// with offsets only, and results can be sorted by relevance or
+// modified_at.
+func (box *Box) SearchPage(ctx context.Context, query string, filter *SearchFilter, opts *ListOptions, options ...RequestOption) (*ListResult[Entity], error) {
+	ctx = withCall(ctx, options)
+	if query == "" {
+		return nil, errors.New("Empty query while using SearchPage")
+	}
+	if err := opts.check("relevance", "modified_at"); err != nil {
+		return nil, err
+	}
+	if opts != nil && (opts.UseMarker || opts.Marker != "") {
+		return nil, errors.New("Search is paged with offsets only")
+	}
+	params := opts.values()
+	params.Set("query", query)
+	filter.values(params)
+	return listPage[Entity](ctx, box, "search", params, nil)
+}
@@ -0,0 +1,293 @@
+package box
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// FileService groups the operations on files, see Box.Files. It can be
+// replaced, e.g. by a fake in tests.
+type FileService interface {
+	// Get returns the file with the given id, with only the fields of
+	// WithFields if given.
+	Get(ctx context.Context, id string, options ...RequestOption) (*File, error)
+	// Delete moves the file to the trash.
+	Delete(ctx context.Context, id string, options ...RequestOption) error
+	// Rename renames the file and returns it updated.
+	Rename(ctx context.Context, id, name string, options ...RequestOption) (*File, error)
+	// Update changes the given attributes of the file and returns it
+	// updated.
+	Update(ctx context.Context, id string, upd *FileUpdate, options ...RequestOption) (*File, error)
+	// Move moves the file into the given folder and returns it updated.
+	Move(ctx context.Context, id, parentId string, options ...RequestOption) (*File, error)
+	// Copy copies the file into the given folder and returns the copy.
+	Copy(ctx context.Context, id, parentId string, options ...RequestOption) (*File, error)
+	// Download writes the content of the file to w.
+	Download(ctx context.Context, id string, w io.Writer, options ...RequestOption) error
+	// Upload uploads the content of r as a new file with the given name
+	// in the given folder and returns it.
+	Upload(ctx context.Context, parentId, name string, r io.Reader, options ...RequestOption) (*File, error)
+	// Versions returns the previous versions of the file, newest first.
+	Versions(ctx context.Context, id string, options ...RequestOption) ([]FileVersion, error)
+}
+
+// fileService is the FileService sending the requests of a box.
+type fileService struct {
+	box *Box
+}
+
+func (s *fileService) Get(ctx context.Context, id string, options ...RequestOption) (*File, error) {
+	f := &File{Id: id}
+	if err := s.get(ctx, f, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *fileService) Delete(ctx context.Context, id string, options ...RequestOption) error {
+	return s.delete(ctx, &File{Id: id}, options)
+}
+
+func (s *fileService) Rename(ctx context.Context, id, name string, options ...RequestOption) (*File, error) {
+	f := &File{Id: id}
+	if err := s.rename(ctx, f, name, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *fileService) Update(ctx context.Context, id string, upd *FileUpdate, options ...RequestOption) (*File, error) {
+	f := &File{Id: id}
+	if err := s.update(ctx, f, upd, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *fileService) Move(ctx context.Context, id, parentId string, options ...RequestOption) (*File, error) {
+	f := &File{Id: id}
+	if err := s.move(ctx, f, &Folder{Id: parentId}, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *fileService) Copy(ctx context.Context, id, parentId string, options ...RequestOption) (*File, error) {
+	return s.copy(ctx, &File{Id: id}, &Folder{Id: parentId}, options)
+}
+
+func (s *fileService) Download(ctx context.Context, id string, w io.Writer, options ...RequestOption) error {
+	return s.download(ctx, &File{Id: id}, w, options)
+}
+
+func (s *fileService) Upload(ctx context.Context, parentId, name string, r io.Reader, options ...RequestOption) (*File, error) {
+	f := &File{Name: name}
+	if err := s.upload(ctx, f, r, &Folder{Id: parentId}, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *fileService) Versions(ctx context.Context, id string, options ...RequestOption) ([]FileVersion, error) {
+	return s.versions(ctx, &File{Id: id}, options)
+}
+
+// FolderService groups the operations on folders, see Box.Folders. It
+// can be replaced, e.g. by a fake in tests.
+type FolderService interface {
+	// Get returns the folder with the given id, with only the fields
+	// of WithFields if given.
+	Get(ctx context.Context, id string, options ...RequestOption) (*Folder, error)
+	// ListItems returns a single page of the items of the folder.
+	ListItems(ctx context.Context, id string, opts *ListOptions, options ...RequestOption) (*ListResult[Entity], error)
+	// Items returns an iterator over the items of the folder.
+	Items(ctx context.Context, id string, opts *ListOptions, options ...RequestOption) *Iterator[Entity]
+	// Create creates a folder with the given name in the parent folder
+	// and returns it.
+	Create(ctx context.Context, parentId, name string, options ...RequestOption) (*Folder, error)
+	// Delete moves the folder and its content to the trash.
+	Delete(ctx context.Context, id string, options ...RequestOption) error
+	// Rename renames the folder and returns it updated.
+	Rename(ctx context.Context, id, name string, options ...RequestOption) (*Folder, error)
+	// Move moves the folder into the given folder and returns it
+	// updated.
+	Move(ctx context.Context, id, parentId string, options ...RequestOption) (*Folder, error)
+	// Copy copies the folder into the given folder and returns the
+	// copy.
+	Copy(ctx context.Context, id, parentId string, options ...RequestOption) (*Folder, error)
+	// Collaborations returns the collaborations of the folder.
+	Collaborations(ctx context.Context, id string, options ...RequestOption) ([]Collaboration, error)
+}
+
+// folderService is the FolderService sending the requests of a box.
+type folderService struct {
+	box *Box
+}
+
+func (s *folderService) Get(ctx context.Context, id string, options ...RequestOption) (*Folder, error) {
+	f := &Folder{Id: id}
+	if err := s.get(ctx, f, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *folderService) ListItems(ctx context.Context, id string, opts *ListOptions, options ...RequestOption) (*ListResult[Entity], error) {
+	return s.itemsPage(ctx, &Folder{Id: id}, opts, options)
+}
+
+func (s *folderService) Items(ctx context.Context, id string, opts *ListOptions, options ...RequestOption) *Iterator[Entity] {
+	return s.items(ctx, &Folder{Id: id}, opts, options)
+}
+
+func (s *folderService) Create(ctx context.Context, parentId, name string, options ...RequestOption) (*Folder, error) {
+	return s.create(ctx, &Folder{Id: parentId}, name, options)
+}
+
+func (s *folderService) Delete(ctx context.Context, id string, options ...RequestOption) error {
+	return s.delete(ctx, &Folder{Id: id}, options)
+}
+
+func (s *folderService) Rename(ctx context.Context, id, name string, options ...RequestOption) (*Folder, error) {
+	f := &Folder{Id: id}
+	if err := s.rename(ctx, f, name, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *folderService) Move(ctx context.Context, id, parentId string, options ...RequestOption) (*Folder, error) {
+	f := &Folder{Id: id}
+	if err := s.move(ctx, f, &Folder{Id: parentId}, options); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *folderService) Copy(ctx context.Context, id, parentId string, options ...RequestOption) (*Folder, error) {
+	return s.copy(ctx, &Folder{Id: id}, &Folder{Id: parentId}, options)
+}
+
+func (s *folderService) Collaborations(ctx context.Context, id string, options ...RequestOption) ([]Collaboration, error) {
+	return s.collaborations(ctx, &Folder{Id: id}, options)
+}
+
+// UserService groups the operations on users, see Box.Users. It can
+// be replaced, e.g. by a fake in tests.
+type UserService interface {
+	// Get returns the user with the given id, with only the fields of
+	// WithFields if given.
+	Get(ctx context.Context, id string, options ...RequestOption) (*User, error)
+	// List returns an iterator over the users of the enterprise.
+	List(ctx context.Context, opts *ListOptions, options ...RequestOption) *Iterator[User]
+	// CreateAppUser creates an app user, see Box.CreateAppUser.
+	CreateAppUser(ctx context.Context, name, externalId string, options ...RequestOption) (*User, error)
+	// FindByLogin finds a user by email address, see
+	// Box.FindUserByLogin.
+	FindByLogin(ctx context.Context, login string, options ...RequestOption) (*User, error)
+	// FindAppUser finds an app user by external id, see
+	// Box.FindAppUser.
+	FindAppUser(ctx context.Context, externalId string, options ...RequestOption) (*User, error)
+}
+
+// userService is the UserService sending the requests of a box.
+type userService struct {
+	box *Box
+}
+
+func (s *userService) Get(ctx context.Context, id string, options ...RequestOption) (*User, error) {
+	u := &User{Id: id}
+	if err := s.get(ctx, u, options); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *userService) List(ctx context.Context, opts *ListOptions, options ...RequestOption) *Iterator[User] {
+	return s.box.UsersIterator(ctx, opts, options...)
+}
+
+func (s *userService) CreateAppUser(ctx context.Context, name, externalId string, options ...RequestOption) (*User, error) {
+	return s.box.CreateAppUser(ctx, name, externalId, options...)
+}
+
+func (s *userService) FindByLogin(ctx context.Context, login string, options ...RequestOption) (*User, error) {
+	return s.box.FindUserByLogin(ctx, login, options...)
+}
+
+func (s *userService) FindAppUser(ctx context.Context, externalId string, options ...RequestOption) (*User, error) {
+	return s.box.FindAppUser(ctx, externalId, options...)
+}
+
+// CollaborationService groups the operations on collaborations, see
+// Box.Collaborations. It can be replaced, e.g. by a fake in tests.
+type CollaborationService interface {
+	// Create gives the user or group, with Id and Type set, access to
+	// the folder with the given role.
+	Create(ctx context.Context, folderId string, accessibleBy *Entity, role string, options ...RequestOption) (*Collaboration, error)
+	// Get returns the collaboration, with only the fields of
+	// WithFields if given.
+	Get(ctx context.Context, id string, options ...RequestOption) (*Collaboration, error)
+	// Update changes the role of the collaboration and returns it
+	// updated.
+	Update(ctx context.Context, id, role string, options ...RequestOption) (*Collaboration, error)
+	// Delete removes the collaboration.
+	Delete(ctx context.Context, id string, options ...RequestOption) error
+}
+
+// collaborationService is the CollaborationService sending the
+// requests of a box.
+type collaborationService struct {
+	box *Box
+}
+
+func (s *collaborationService) Create(ctx context.Context, folderId string, accessibleBy *Entity, role string, options ...RequestOption) (*Collaboration, error) {
+	return s.create(ctx, &Folder{Id: folderId}, accessibleBy, role, nil, options)
+}
+
+func (s *collaborationService) Get(ctx context.Context, id string, options ...RequestOption) (*Collaboration, error) {
+	c := &Collaboration{Id: id}
+	if err := s.get(ctx, c, options); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *collaborationService) Update(ctx context.Context, id, role string, options ...RequestOption) (*Collaboration, error) {
+	c := &Collaboration{Id: id}
+	if err := s.update(ctx, c, role, nil, options); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *collaborationService) Delete(ctx context.Context, id string, options ...RequestOption) error {
+	return s.delete(ctx, &Collaboration{Id: id}, options)
+}
+
+// ifMatchOptions returns the options of a deprecated method changing an
+// item with the given etag, sending it as If-Match when the box has
+// MatchETags, as the service it delegates to only gets the id.
+func (box *Box) ifMatchOptions(etag string, options []RequestOption) []RequestOption {
+	if !box.MatchETags || etag == "" {
+		return options
+	}
+	return append([]RequestOption{WithIfMatch(etag)}, options...)
+}
+
+// ifNoneMatchOptions is ifMatchOptions for a deprecated method getting
+// an item, sending the etag as If-None-Match.
+func (box *Box) ifNoneMatchOptions(etag string, options []RequestOption) []RequestOption {
+	if !box.MatchETags || etag == "" {
+		return options
+	}
+	return append([]RequestOption{WithHeader(http.Header{"If-None-Match": {etag}})}, options...)
+}
+
+// withContentType makes the upload of the call send the content with
+// the given MIME type, the ContentType of the file of a deprecated
+// File.Upload.
+func withContentType(contentType string) RequestOption {
+	return func(o *callOptions) { o.contentType = contentType }
+}
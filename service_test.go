@@ -0,0 +1,121 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// fakeFiles is a FileService getting files from memory.
+type fakeFiles struct {
+	FileService
+	files map[string]*File
+}
+
+func (s *fakeFiles) Get(ctx context.Context, id string, options ...RequestOption) (*File, error) {
+	f, ok := s.files[id]
+	if !ok {
+		return nil, NOT_FOUND
+	}
+	got := *f
+	return &got, nil
+}
+
+func TestDeprecatedMethodsUseServices(t *testing.T) {
+	b := NewBox()
+	b.Files = &fakeFiles{files: map[string]*File{"1": {Id: "1", Name: "a.txt"}}}
+	f := &File{Id: "1"}
+	if err := f.Get(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+	if f.Name != "a.txt" {
+		t.Errorf("got file %q, want the one of the FileService", f.Name)
+	}
+}
+
+func TestDeprecatedMethodsMatchETags(t *testing.T) {
+	var header http.Header
+	b := newTestBox(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		io.WriteString(w, `{"type":"file","id":"1","etag":"2","name":"b.txt"}`)
+	}))
+	b.MatchETags = true
+	ctx := context.Background()
+
+	f := &File{Id: "1", ETag: "1"}
+	if err := f.Rename(ctx, b, "b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("If-Match"); got != "1" {
+		t.Errorf("rename sent If-Match %q, want the etag of the file", got)
+	}
+	if f.Name != "b.txt" || f.ETag != "2" {
+		t.Errorf("renamed file is %q with etag %q, want the one returned", f.Name, f.ETag)
+	}
+
+	if err := f.Get(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("If-None-Match"); got != "2" {
+		t.Errorf("get sent If-None-Match %q, want the etag of the file", got)
+	}
+
+	folder := &Folder{Id: "1", ETag: "3"}
+	if err := folder.Delete(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get("If-Match"); got != "3" {
+		t.Errorf("delete sent If-Match %q, want the etag of the folder", got)
+	}
+}
+
+func TestRenameChecksName(t *testing.T) {
+	tests := []struct {
+		name     string
+		sanitize bool
+		ok       bool
+	}{
+		{"report.pdf", false, true},
+		{"a/b", false, false},
+		{"..", false, false},
+		{"a/b", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			b := newTestBox(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Name string `json:"name"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				sent = append(sent, body.Name)
+				io.WriteString(w, `{"id":"1"}`)
+			}))
+			b.SanitizeNames = tt.sanitize
+			ctx := context.Background()
+			_, ferr := b.Files.Rename(ctx, "1", tt.name)
+			_, derr := b.Folders.Rename(ctx, "1", tt.name)
+			for _, err := range []error{ferr, derr} {
+				if tt.ok && err != nil {
+					t.Errorf("rename failed: %v", err)
+				}
+				if !tt.ok && err == nil {
+					t.Error("renamed, want an invalid name error")
+				}
+			}
+			if !tt.ok {
+				if len(sent) != 0 {
+					t.Errorf("sent %d renames, want none", len(sent))
+				}
+				return
+			}
+			for _, name := range sent {
+				if want := SanitizeName(tt.name); tt.sanitize && name != want {
+					t.Errorf("renamed to %q, want %q", name, want)
+				}
+			}
+		})
+	}
+}
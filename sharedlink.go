@@ -0,0 +1,186 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Access levels of shared links.
+const (
+	ACCESS_OPEN          = "open"          // Anyone with the link.
+	ACCESS_COMPANY       = "company"       // The users of the enterprise with the link.
+	ACCESS_COLLABORATORS = "collaborators" // The collaborators of the item only.
+)
+
+// sharedLink is a shared link with its password, if any.
+type sharedLink struct {
+	url      string
+	password string
+}
+
+// WithSharedLink makes the requests of the call access items through
+// the given shared link, e.g. to Get or Download a file only shared by
+// link with the user. The password is empty for links without one.
+func WithSharedLink(link, password string) RequestOption {
+	return func(o *callOptions) { o.sharedLink = &sharedLink{link, password} }
+}
+
+// setSharedLinkHeader sets the BoxApi header of the request to the
+// shared link.
+func setSharedLinkHeader(r *http.Request, l *sharedLink) {
+	v := "shared_link=" + l.url
+	if l.password != "" {
+		v += "&shared_link_password=" + l.password
+	}
+	r.Header.Set("BoxApi", v)
+}
+
+// SharedItem returns the file or folder the shared link points to.
+func (box *Box) SharedItem(ctx context.Context, link, password string, options ...RequestOption) (*Entity, error) {
+	ctx = withCall(ctx, append([]RequestOption{WithSharedLink(link, password)}, options...))
+	if link == "" {
+		return nil, errors.New("Empty link while using SharedItem")
+	}
+	body, err := box.doRequest(ctx, "GET", "shared_items", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	item := &Entity{}
+	err = box.decode(body, item)
+	return item, err
+}
+
+// SharedLinkAccessLevels returns the shared link access levels the
+// policies of the user and the enterprise allow on the file, e.g.
+// without open when the enterprise forbids public links. Note that only
+// Id is required apriori.
+func (f *File) SharedLinkAccessLevels(ctx context.Context, box *Box, options ...RequestOption) ([]string, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using SharedLinkAccessLevels")
+	}
+	return box.allowedAccessLevels(ctx, fmt.Sprintf("files/%s", f.Id))
+}
+
+// SharedLinkAccessLevels returns the shared link access levels the
+// policies of the user and the enterprise allow on the folder. Note
+// that only Id is required apriori.
+func (f *Folder) SharedLinkAccessLevels(ctx context.Context, box *Box, options ...RequestOption) ([]string, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using SharedLinkAccessLevels")
+	}
+	return box.allowedAccessLevels(ctx, fmt.Sprintf("folders/%s", f.Id))
+}
+
+// allowedAccessLevels reads the allowed shared link access levels of
+// the item at the given path.
+func (box *Box) allowedAccessLevels(ctx context.Context, rawurl string) ([]string, error) {
+	body, err := box.doRequest(ctx, "GET", rawurl, fieldsParams([]string{"allowed_shared_link_access_levels"}), nil)
+	if err != nil {
+		return nil, err
+	}
+	var item struct {
+		Allowed []string `json:"allowed_shared_link_access_levels"`
+	}
+	err = box.decode(body, &item)
+	return item.Allowed, err
+}
+
+// checkSharedLinkAccess checks that the access level is allowed on the
+// item at the given path, reading the allowed levels unless known,
+// when the box has CheckSharedLinkAccess. An empty access leaves the
+// level to Box.
+func (box *Box) checkSharedLinkAccess(ctx context.Context, rawurl string, allowed []string, access string) error {
+	if !box.CheckSharedLinkAccess || access == "" {
+		return nil
+	}
+	if allowed == nil {
+		var err error
+		if allowed, err = box.allowedAccessLevels(ctx, rawurl); err != nil {
+			return err
+		}
+	}
+	for _, a := range allowed {
+		if a == access {
+			return nil
+		}
+	}
+	return &SharedLinkAccessError{access, allowed}
+}
+
+// SharedLinkPermissions are the permissions of a shared link to change.
+// Nil fields are left as they are, so a single permission can be set
+// or cleared, see Bool.
+type SharedLinkPermissions struct {
+	CanDownload *bool `json:"can_download,omitempty"` // Whether the item can be downloaded through the link.
+	CanPreview  *bool `json:"can_preview,omitempty"`  // Whether the item can be previewed through the link.
+	CanEdit     *bool `json:"can_edit,omitempty"`     // Whether the file can be edited through the link, only for files.
+}
+
+// SharedLinkUpdate holds the settings of a shared link to create or
+// change. Empty fields are left as they are.
+type SharedLinkUpdate struct {
+	Access      string                 `json:"access,omitempty"`      // One of the ACCESS levels.
+	Password    string                 `json:"password,omitempty"`    // The password to protect the link with.
+	VanityName  string                 `json:"vanity_name,omitempty"` // The custom name of the link url.
+	UnsharedAt  *BoxTime               `json:"unshared_at,omitempty"` // When the link is removed by itself.
+	Permissions *SharedLinkPermissions `json:"permissions,omitempty"` // The permissions to change.
+}
+
+// Bool returns a pointer to v, to fill the tri-state fields such as
+// those of SharedLinkPermissions and HubUpdate.
+func Bool(v bool) *bool {
+	return &v
+}
+
+// UpdateSharedLink creates the shared link of the file or changes the
+// given settings of it. Note that only Id is required apriori. The
+// SharedLink of the file is populated after the call.
+func (f *File) UpdateSharedLink(ctx context.Context, box *Box, upd *SharedLinkUpdate, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using UpdateSharedLink")
+	}
+	rawurl := fmt.Sprintf("files/%s", f.Id)
+	if err := box.checkSharedLinkAccess(ctx, rawurl, f.AllowedSharedLinkAccessLevels, upd.Access); err != nil {
+		return err
+	}
+	body, err := box.putSharedLink(ctx, rawurl, upd)
+	if err != nil {
+		return err
+	}
+	return box.decode(body, f)
+}
+
+// UpdateSharedLink creates the shared link of the folder or changes the
+// given settings of it. Note that only Id is required apriori. The
+// SharedLink of the folder is populated after the call.
+func (f *Folder) UpdateSharedLink(ctx context.Context, box *Box, upd *SharedLinkUpdate, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using UpdateSharedLink")
+	}
+	if upd.Permissions != nil && upd.Permissions.CanEdit != nil && *upd.Permissions.CanEdit {
+		return errors.New("Folder shared links cannot allow editing")
+	}
+	rawurl := fmt.Sprintf("folders/%s", f.Id)
+	if err := box.checkSharedLinkAccess(ctx, rawurl, f.AllowedSharedLinkAccessLevels, upd.Access); err != nil {
+		return err
+	}
+	body, err := box.putSharedLink(ctx, rawurl, upd)
+	if err != nil {
+		return err
+	}
+	return box.decode(body, f)
+}
+
+// putSharedLink sends the shared link update of the item at the given
+// path and returns the item with its shared link.
+func (box *Box) putSharedLink(ctx context.Context, rawurl string, upd *SharedLinkUpdate) ([]byte, error) {
+	reqBody, _ := json.Marshal(map[string]*SharedLinkUpdate{"shared_link": upd})
+	return box.doRequest(ctx, "PUT", rawurl, fieldsParams([]string{"shared_link"}), reqBody)
+}
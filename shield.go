@@ -0,0 +1,286 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type ShieldBarrier struct {
+	Id         string   `json:"id,omitempty"`         // The id of the information barrier.
+	Type       string   `json:"type,omitempty"`       // Type of the object, always shield_information_barrier.
+	Enterprise *Entity  `json:"enterprise,omitempty"` // The enterprise the barrier belongs to.
+	Status     string   `json:"status,omitempty"`     // Status of the barrier (draft, pending, enabled, disabled...).
+	CreatedAt  *BoxTime `json:"created_at,omitempty"` // When the barrier was created.
+	CreatedBy  *Entity  `json:"created_by,omitempty"` // The user who created the barrier.
+	UpdatedAt  *BoxTime `json:"updated_at,omitempty"` // When the barrier was last updated.
+	UpdatedBy  *Entity  `json:"updated_by,omitempty"` // The user who last updated the barrier.
+	EnabledAt  *BoxTime `json:"enabled_at,omitempty"` // When the barrier was enabled.
+	EnabledBy  *Entity  `json:"enabled_by,omitempty"` // The user who enabled the barrier.
+}
+
+type ShieldBarrierReport struct {
+	Id        string         `json:"id,omitempty"`                         // The id of the report.
+	Type      string         `json:"type,omitempty"`                       // Type of the object, always shield_information_barrier_report.
+	Barrier   *ShieldBarrier `json:"shield_information_barrier,omitempty"` // The barrier the report is about.
+	Status    string         `json:"status,omitempty"`                     // Status of the report (pending, error, done, cancelled).
+	Details   *ReportDetails `json:"details,omitempty"`                    // The results of the report.
+	CreatedAt *BoxTime       `json:"created_at,omitempty"`                 // When the report was created.
+	CreatedBy *Entity        `json:"created_by,omitempty"`                 // The user who created the report.
+	UpdatedAt *BoxTime       `json:"updated_at,omitempty"`                 // When the report was last updated.
+}
+
+type ReportDetails struct {
+	Details struct {
+		FolderCount int64 `json:"folder_count,omitempty"` // Number of folders affected by the barrier.
+	} `json:"details,omitempty"`
+}
+
+type ShieldSegment struct {
+	Id          string         `json:"id,omitempty"`                         // The id of the segment.
+	Type        string         `json:"type,omitempty"`                       // Type of the object, always shield_information_barrier_segment.
+	Barrier     *ShieldBarrier `json:"shield_information_barrier,omitempty"` // The barrier the segment belongs to.
+	Name        string         `json:"name,omitempty"`                       // The name of the segment.
+	Description string         `json:"description,omitempty"`                // The description of the segment.
+	CreatedAt   *BoxTime       `json:"created_at,omitempty"`                 // When the segment was created.
+	CreatedBy   *Entity        `json:"created_by,omitempty"`                 // The user who created the segment.
+	UpdatedAt   *BoxTime       `json:"updated_at,omitempty"`                 // When the segment was last updated.
+	UpdatedBy   *Entity        `json:"updated_by,omitempty"`                 // The user who last updated the segment.
+}
+
+type ShieldRestriction struct {
+	Id                string         `json:"id,omitempty"`                                 // The id of the restriction.
+	Type              string         `json:"type,omitempty"`                               // Type of the object, always shield_information_barrier_segment_restriction.
+	Segment           *ShieldSegment `json:"shield_information_barrier_segment,omitempty"` // The segment the restriction applies to.
+	RestrictedSegment *ShieldSegment `json:"restricted_segment,omitempty"`                 // The segment that can not be communicated with.
+	Barrier           *ShieldBarrier `json:"shield_information_barrier,omitempty"`         // The barrier the restriction belongs to.
+	CreatedAt         *BoxTime       `json:"created_at,omitempty"`                         // When the restriction was created.
+	CreatedBy         *Entity        `json:"created_by,omitempty"`                         // The user who created the restriction.
+	UpdatedAt         *BoxTime       `json:"updated_at,omitempty"`                         // When the restriction was last updated.
+	UpdatedBy         *Entity        `json:"updated_by,omitempty"`                         // The user who last updated the restriction.
+}
+
+type ShieldList struct {
+	Id          string             `json:"id,omitempty"`          // The id of the shield list.
+	Type        string             `json:"type,omitempty"`        // Type of the object, always shield_list.
+	Name        string             `json:"name,omitempty"`        // The name of the shield list.
+	Description string             `json:"description,omitempty"` // The description of the shield list.
+	Enterprise  *Entity            `json:"enterprise,omitempty"`  // The enterprise the shield list belongs to.
+	Content     *ShieldListContent `json:"content,omitempty"`     // The entries of the shield list.
+	CreatedAt   *BoxTime           `json:"created_at,omitempty"`  // When the shield list was created.
+	UpdatedAt   *BoxTime           `json:"updated_at,omitempty"`  // When the shield list was last updated.
+}
+
+// ShieldListContent holds the entries of a shield list. Only the field
+// matching Type is populated.
+type ShieldListContent struct {
+	Type         string   `json:"type,omitempty"`            // Type of the entries (country, domain, email, integration, ip).
+	CountryCodes []string `json:"country_codes,omitempty"`   // Country codes of a country list.
+	Domains      []string `json:"domains,omitempty"`         // Domains of a domain list.
+	Emails       []string `json:"email_addresses,omitempty"` // Email addresses of an email list.
+	IPAddresses  []string `json:"ip_addresses,omitempty"`    // IP addresses or ranges of an ip list.
+	Integrations []Entity `json:"integrations,omitempty"`    // Integrations of an integration list.
+}
+
+// ShieldBarriers returns all the information barriers of the
+// enterprise.
+func (box *Box) ShieldBarriers(ctx context.Context, options ...RequestOption) ([]ShieldBarrier, error) {
+	ctx = withCall(ctx, options)
+	var all []ShieldBarrier
+	err := box.listAll(ctx, "shield_information_barriers", nil, nil, func(entries json.RawMessage) error {
+		var page []ShieldBarrier
+		err := box.decode(entries, &page)
+		all = append(all, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// ShieldBarriersPage returns a single page of the information barriers
+// of the enterprise with its paging information. Shield listings are
+// paged with markers only.
+func (box *Box) ShieldBarriersPage(ctx context.Context, opts *ListOptions, options ...RequestOption) (*ListResult[ShieldBarrier], error) {
+	ctx = withCall(ctx, options)
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	return listPage[ShieldBarrier](ctx, box, "shield_information_barriers", opts.markerValues(), nil)
+}
+
+// Get populates the fields of the barrier. Note that only Id is
+// required apriori.
+func (b *ShieldBarrier) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if b.Id == "" {
+		return errors.New("Empty id while using Get")
+	}
+	rawurl := fmt.Sprintf("shield_information_barriers/%s", b.Id)
+	body, err := box.doRequest(ctx, "GET", rawurl, nil, nil)
+
+	if err == nil {
+		err = box.decode(body, b)
+		return err
+	}
+	return err
+}
+
+// Reports returns all the reports generated for the barrier. Note that
+// only Id is required apriori.
+func (b *ShieldBarrier) Reports(ctx context.Context, box *Box, options ...RequestOption) ([]ShieldBarrierReport, error) {
+	ctx = withCall(ctx, options)
+	if b.Id == "" {
+		return nil, errors.New("Empty id while using Reports")
+	}
+	var all []ShieldBarrierReport
+	params := url.Values{"shield_information_barrier_id": {b.Id}}
+	err := box.listAll(ctx, "shield_information_barrier_reports", params, nil, func(entries json.RawMessage) error {
+		var page []ShieldBarrierReport
+		err := box.decode(entries, &page)
+		all = append(all, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// ReportsPage returns a single page of the reports of the barrier with
+// its paging information. Note that only Id is required apriori.
+func (b *ShieldBarrier) ReportsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[ShieldBarrierReport], error) {
+	ctx = withCall(ctx, options)
+	if b.Id == "" {
+		return nil, errors.New("Empty id while using ReportsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	params := opts.markerValues()
+	params.Set("shield_information_barrier_id", b.Id)
+	return listPage[ShieldBarrierReport](ctx, box, "shield_information_barrier_reports", params, nil)
+}
+
+// Segments returns all the segments of the barrier. Note that only Id
+// is required apriori.
+func (b *ShieldBarrier) Segments(ctx context.Context, box *Box, options ...RequestOption) ([]ShieldSegment, error) {
+	ctx = withCall(ctx, options)
+	if b.Id == "" {
+		return nil, errors.New("Empty id while using Segments")
+	}
+	var all []ShieldSegment
+	params := url.Values{"shield_information_barrier_id": {b.Id}}
+	err := box.listAll(ctx, "shield_information_barrier_segments", params, nil, func(entries json.RawMessage) error {
+		var page []ShieldSegment
+		err := box.decode(entries, &page)
+		all = append(all, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// SegmentsPage returns a single page of the segments of the barrier
+// with its paging information. Note that only Id is required apriori.
+func (b *ShieldBarrier) SegmentsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[ShieldSegment], error) {
+	ctx = withCall(ctx, options)
+	if b.Id == "" {
+		return nil, errors.New("Empty id while using SegmentsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	params := opts.markerValues()
+	params.Set("shield_information_barrier_id", b.Id)
+	return listPage[ShieldSegment](ctx, box, "shield_information_barrier_segments", params, nil)
+}
+
+// Restrictions returns all the restrictions applying to the
+// segment. Note that only Id is required apriori.
+func (s *ShieldSegment) Restrictions(ctx context.Context, box *Box, options ...RequestOption) ([]ShieldRestriction, error) {
+	ctx = withCall(ctx, options)
+	if s.Id == "" {
+		return nil, errors.New("Empty id while using Restrictions")
+	}
+	var all []ShieldRestriction
+	params := url.Values{"shield_information_barrier_segment_id": {s.Id}}
+	err := box.listAll(ctx, "shield_information_barrier_segment_restrictions", params, nil, func(entries json.RawMessage) error {
+		var page []ShieldRestriction
+		err := box.decode(entries, &page)
+		all = append(all, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// RestrictionsPage returns a single page of the restrictions applying
+// to the segment with its paging information. Note that only Id is
+// required apriori.
+func (s *ShieldSegment) RestrictionsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[ShieldRestriction], error) {
+	ctx = withCall(ctx, options)
+	if s.Id == "" {
+		return nil, errors.New("Empty id while using RestrictionsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	params := opts.markerValues()
+	params.Set("shield_information_barrier_segment_id", s.Id)
+	return listPage[ShieldRestriction](ctx, box, "shield_information_barrier_segment_restrictions", params, nil)
+}
+
+// ShieldLists returns all the shield lists of the enterprise.
+func (box *Box) ShieldLists(ctx context.Context, options ...RequestOption) ([]ShieldList, error) {
+	ctx = withCall(ctx, options)
+	var all []ShieldList
+	header := http.Header{"Box-Version": {shieldListsVersion}}
+	err := box.listAll(ctx, "shield_lists", nil, header, func(entries json.RawMessage) error {
+		var page []ShieldList
+		err := box.decode(entries, &page)
+		all = append(all, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// ShieldListsPage returns a single page of the shield lists of the
+// enterprise with its paging information.
+func (box *Box) ShieldListsPage(ctx context.Context, opts *ListOptions, options ...RequestOption) (*ListResult[ShieldList], error) {
+	ctx = withCall(ctx, options)
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	header := http.Header{"Box-Version": {shieldListsVersion}}
+	return listPage[ShieldList](ctx, box, "shield_lists", opts.markerValues(), header)
+}
+
+// Get populates the fields of the shield list. Note that only Id is
+// required apriori.
+func (l *ShieldList) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if l.Id == "" {
+		return errors.New("Empty id while using Get")
+	}
+	rawurl := fmt.Sprintf("shield_lists/%s", l.Id)
+	header := http.Header{"Box-Version": {shieldListsVersion}}
+	body, err := box.doRequestHeader(ctx, "GET", rawurl, nil, header, nil)
+
+	if err == nil {
+		err = box.decode(body, l)
+		return err
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// FolderTemplate describes a folder to provision with ApplyTemplate,
+// along with its sub folders.
+type FolderTemplate struct {
+	Name           string                  // The name of the folder.
+	Description    string                  // The description of the folder, left as is if empty.
+	Metadata       []TemplateMetadata      // The metadata instances to set on the folder.
+	Collaborations []TemplateCollaboration // The collaborators to give access to the folder.
+	Folders        []FolderTemplate        // The sub folders.
+}
+
+// TemplateMetadata is a metadata instance of a FolderTemplate, see
+// Folder.SetMetadata.
+type TemplateMetadata struct {
+	Scope    string                 // The scope of the metadata template, e.g. enterprise.
+	Template string                 // The key of the metadata template.
+	Values   map[string]interface{} // The fields to set.
+}
+
+// TemplateCollaboration is a collaboration of a FolderTemplate, see
+// Folder.Collaborate.
+type TemplateCollaboration struct {
+	AccessibleBy *Entity // The user or group, with Id and Type set.
+	Role         string  // The level of access, e.g. editor or viewer.
+}
+
+// ApplyTemplate provisions the folder tree described by tmpl under
+// parent and returns the top folder. It is idempotent: existing
+// folders are reused, descriptions and metadata are set again and
+// collaborators already given access only get their role updated, so
+// a template can be applied again after a failure or a change. Note
+// that only Id of the parent folder is required apriori.
+func (box *Box) ApplyTemplate(ctx context.Context, parent *Folder, tmpl *FolderTemplate, options ...RequestOption) (*Folder, error) {
+	ctx = withCall(ctx, options)
+	if parent.Id == "" {
+		return nil, errors.New("Empty parent id while using ApplyTemplate")
+	}
+	if tmpl.Name == "" {
+		return nil, errors.New("Empty name while using ApplyTemplate")
+	}
+
+	fold, err := parent.CreateOrGet(ctx, box, tmpl.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if tmpl.Description != "" && tmpl.Description != fold.Description {
+		reqBody, _ := json.Marshal(Folder{Description: tmpl.Description})
+		rawurl := fmt.Sprintf("folders/%s", fold.Id)
+		body, err := box.doRequest(ctx, "PUT", rawurl, nil, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		if err = box.decode(body, fold); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, md := range tmpl.Metadata {
+		if err = fold.SetMetadata(ctx, box, md.Scope, md.Template, md.Values); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(tmpl.Collaborations) > 0 {
+		if err = box.applyCollaborations(ctx, fold, tmpl.Collaborations); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range tmpl.Folders {
+		if _, err = box.ApplyTemplate(ctx, fold, &tmpl.Folders[i]); err != nil {
+			return nil, err
+		}
+	}
+	return fold, nil
+}
+
+// applyCollaborations gives the collaborators access to the folder,
+// updating the role of the ones which already have it.
+func (box *Box) applyCollaborations(ctx context.Context, fold *Folder, collabs []TemplateCollaboration) error {
+	existing, err := fold.Collaborations(ctx, box)
+	if err != nil {
+		return err
+	}
+	for _, tc := range collabs {
+		if tc.AccessibleBy == nil {
+			return errors.New("Empty collaborator while using ApplyTemplate")
+		}
+		var found *Collaboration
+		for i := range existing {
+			if existing[i].AccessibleBy != nil && existing[i].AccessibleBy.Id == tc.AccessibleBy.Id {
+				found = &existing[i]
+				break
+			}
+		}
+		if found == nil {
+			_, err = fold.Collaborate(ctx, box, tc.AccessibleBy, tc.Role)
+		} else if found.Role != tc.Role {
+			err = found.Update(ctx, box, tc.Role)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
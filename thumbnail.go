@@ -0,0 +1,112 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Formats of thumbnails.
+const (
+	THUMBNAIL_PNG = "png"
+	THUMBNAIL_JPG = "jpg"
+)
+
+// Thumbnail is the image of a file returned by File.Thumbnail.
+type Thumbnail struct {
+	Data        []byte // The image.
+	ContentType string // The MIME type of the image.
+	Placeholder bool   // Whether the image is a generic icon because Box has no thumbnail of the file, or not yet.
+}
+
+// Thumbnail gets the thumbnail of the file in the given format, png or
+// jpg, within the given bounds, zero leaving a bound to Box. While Box
+// is generating the thumbnail it waits up to the AcceptedTimeout of the
+// box, then returns the placeholder icon Box sends meanwhile, as it
+// does when no thumbnail can be generated. Note that only Id is
+// required apriori.
+func (f *File) Thumbnail(ctx context.Context, box *Box, format string, minWidth, minHeight, maxWidth, maxHeight int, options ...RequestOption) (*Thumbnail, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using Thumbnail")
+	}
+	if format != THUMBNAIL_PNG && format != THUMBNAIL_JPG {
+		return nil, fmt.Errorf("Unsupported thumbnail format %q", format)
+	}
+
+	params := url.Values{}
+	for name, v := range map[string]int{"min_width": minWidth, "min_height": minHeight, "max_width": maxWidth, "max_height": maxHeight} {
+		if v > 0 {
+			params.Set(name, strconv.Itoa(v))
+		}
+	}
+	path := fmt.Sprintf("files/%s/thumbnail.%s", f.Id, format)
+	rawurl := joinURL(box.APIURL, path)
+	if len(params) > 0 {
+		rawurl += "?" + params.Encode()
+	}
+
+	ctx, end := startSpan(ctx, "GET", path)
+	var deadline time.Time
+	for attempt := 0; ; attempt++ {
+		thumb, retryAfter, status, err := box.thumbnail(ctx, rawurl)
+		if status == http.StatusAccepted && box.AcceptedTimeout > 0 {
+			if deadline.IsZero() {
+				deadline = time.Now().Add(box.AcceptedTimeout)
+			}
+			if time.Now().Before(deadline) {
+				if err = sleep(ctx, box.pollDelay(retryAfter)); err == nil {
+					continue
+				}
+			}
+		}
+		end(status, attempt, err)
+		return thumb, err
+	}
+}
+
+// thumbnail requests the thumbnail at rawurl once. It also returns the
+// Retry-After and the status of the response.
+func (box *Box) thumbnail(ctx context.Context, rawurl string) (*Thumbnail, string, int, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", rawurl, nil)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	response, err := box.client().Do(request)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer closeResponse(response)
+
+	retryAfter := response.Header.Get("Retry-After")
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+	default:
+		// Read the error details sent by Box.
+		if _, err = box.getResponse(response); err == nil {
+			err = toError(response.StatusCode)
+		}
+		return nil, retryAfter, response.StatusCode, err
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, retryAfter, response.StatusCode, err
+	}
+	return &Thumbnail{
+		Data:        data,
+		ContentType: response.Header.Get("Content-Type"),
+		// Box redirects to an icon when it has no thumbnail.
+		Placeholder: response.StatusCode == http.StatusAccepted || redirected(request, response),
+	}, retryAfter, response.StatusCode, nil
+}
+
+// redirected checks if response is the answer to a redirect of request.
+func redirected(request *http.Request, response *http.Response) bool {
+	return response.Request != nil && response.Request.URL.String() != request.URL.String()
+}
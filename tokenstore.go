@@ -0,0 +1,67 @@
+package box
+
+import (
+	"encoding/json"
+	"golang.org/x/oauth2"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// TokenStore persists the token of a box so that it survives process
+// restarts, see SetTokenStore. Implementations must be safe for
+// concurrent use.
+type TokenStore interface {
+	// Load returns the saved token, or nil if there is none.
+	Load() (*oauth2.Token, error)
+	// Save replaces the saved token. A nil token clears it.
+	Save(token *oauth2.Token) error
+}
+
+// FileTokenStore is a TokenStore saving the token as a json file only
+// readable by its owner.
+type FileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore returns a store saving to the file at path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+func (s *FileTokenStore) Load() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	token := &oauth2.Token{}
+	if err = json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Save writes the token to a temporary file renamed over the store
+// file, so that an interruption never loses the saved token. A nil
+// token removes the file.
+func (s *FileTokenStore) Save(token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if token == nil {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
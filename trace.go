@@ -0,0 +1,32 @@
+package box
+
+import (
+	"context"
+	"strings"
+)
+
+// startSpan starts tracing an api call of the given method on the path
+// relative to the api root, returning the context of the call and the
+// function ending it with the last status received, the number of
+// retries and the error of the call. It does nothing unless the package
+// is built with the otel tag, see otel.go.
+var startSpan = func(ctx context.Context, method, path string) (context.Context, func(status, retries int, err error)) {
+	return ctx, func(int, int, error) {}
+}
+
+// operation returns path with the ids of the items replaced by a
+// placeholder, such as "folders/{id}/items", and the first of the ids.
+func operation(path string) (string, string) {
+	var id string
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if p == "" || strings.Trim(p, "0123456789") != "" {
+			continue
+		}
+		if id == "" {
+			id = p
+		}
+		parts[i] = "{id}"
+	}
+	return strings.Join(parts, "/"), id
+}
@@ -0,0 +1,97 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// daysUntilPurge returns the number of days, rounded up, from now to
+// purgedAt, and false when the item is not in the trash.
+func daysUntilPurge(purgedAt *BoxTime, now time.Time) (int, bool) {
+	if purgedAt == nil {
+		return 0, false
+	}
+	left := time.Time(*purgedAt).Sub(now)
+	if left <= 0 {
+		return 0, true
+	}
+	return int(math.Ceil(left.Hours() / 24)), true
+}
+
+// DaysUntilPurge returns the number of days, rounded up, before the
+// trashed file is permanently deleted, and false when the file is not
+// in the trash or PurgedAt was not requested.
+func (f *File) DaysUntilPurge() (int, bool) {
+	return daysUntilPurge(f.PurgedAt, time.Now())
+}
+
+// DaysUntilPurge returns the number of days, rounded up, before the
+// trashed folder is permanently deleted, and false when the folder is
+// not in the trash or PurgedAt was not requested.
+func (f *Folder) DaysUntilPurge() (int, bool) {
+	return daysUntilPurge(f.PurgedAt, time.Now())
+}
+
+// DaysUntilPurge returns the number of days, rounded up, before the
+// trashed item is permanently deleted, and false when the item is not
+// in the trash or PurgedAt was not requested.
+func (e *Entity) DaysUntilPurge() (int, bool) {
+	return daysUntilPurge(e.PurgedAt, time.Now())
+}
+
+// PurgeFromTrash permanently deletes the trashed file. Note that only
+// Id is required apriori.
+func (f *File) PurgeFromTrash(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using PurgeFromTrash")
+	}
+	rawurl := fmt.Sprintf("files/%s/trash", f.Id)
+	_, err := box.doRequest(ctx, "DELETE", rawurl, nil, nil)
+	return box.deleted(err)
+}
+
+// PurgeFromTrash permanently deletes the trashed folder and its
+// content. Note that only Id is required apriori.
+func (f *Folder) PurgeFromTrash(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using PurgeFromTrash")
+	}
+	rawurl := fmt.Sprintf("folders/%s/trash", f.Id)
+	_, err := box.doRequest(ctx, "DELETE", rawurl, nil, nil)
+	return box.deleted(err)
+}
+
+// TrashPurgingWithin returns the items in the trash which will be
+// permanently deleted within the given number of days, e.g. to warn
+// about them, soonest first.
+func (box *Box) TrashPurgingWithin(ctx context.Context, days int, options ...RequestOption) ([]Entity, error) {
+	ctx = withCall(ctx, options)
+	limit := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+	opts := &ListOptions{
+		Limit:     1000,
+		Fields:    []string{"name", "type", "size", "trashed_at", "purged_at"},
+		Sort:      "date",
+		Direction: "ASC",
+	}
+	var purging []Entity
+	it := box.TrashIterator(ctx, opts)
+	for it.Next() {
+		e := it.Item()
+		if e.PurgedAt != nil && !time.Time(*e.PurgedAt).After(limit) {
+			purging = append(purging, e)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(purging, func(i, j int) bool {
+		return time.Time(*purging[i].PurgedAt).Before(time.Time(*purging[j].PurgedAt))
+	})
+	return purging, nil
+}
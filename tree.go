@@ -0,0 +1,530 @@
+package box
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SymlinkPolicy tells tree operations what to do with symbolic links.
+type SymlinkPolicy int
+
+const (
+	SymlinkSkip   SymlinkPolicy = iota // Skip symbolic links (default).
+	SymlinkFollow                      // Upload the target of symbolic links.
+	SymlinkError                       // Abort the operation on symbolic links.
+)
+
+// ConflictPolicy tells DownloadTree what to do with a file changed both
+// locally and on Box since the last run, as recorded by the State of
+// the TreeOptions.
+type ConflictPolicy int
+
+const (
+	ConflictKeepRemote ConflictPolicy = iota // Overwrite the local file (default).
+	ConflictKeepLocal                        // Keep the local file, skipping the download.
+	ConflictKeepBoth                         // Rename the local file to a conflicted copy, then download.
+	ConflictFail                             // Record the file as failed.
+)
+
+// DeletionPolicy tells the tree operations whether to propagate the
+// deletion of files transferred by an earlier run, as recorded by the
+// State of the TreeOptions.
+type DeletionPolicy int
+
+const (
+	DeleteNone     DeletionPolicy = iota // Never delete anything (default).
+	DeleteToLocal                        // DownloadTree deletes the local files deleted on Box.
+	DeleteToRemote                       // UploadTree deletes from Box the files deleted locally.
+	DeleteBoth                           // Both of the above.
+)
+
+// Actions recorded in a TreeReport.
+const (
+	TreeUploaded   = "uploaded"   // The file was uploaded.
+	TreeDownloaded = "downloaded" // The file was downloaded and matched its checksum.
+	TreeCreated    = "created"    // The folder was created (or already existed).
+	TreeSkipped    = "skipped"    // The item was skipped, see Reason.
+	TreeDeleted    = "deleted"    // The file was deleted as it was on the other side.
+	TreeFailed     = "failed"     // The item could not be transferred, see Err.
+)
+
+// TreeOptions configures the tree operations. The zero value uploads
+// everything but hidden files, skips symbolic links and creates empty
+// folders.
+type TreeOptions struct {
+	Filter        *Filter        // Rules selecting the items to transfer.
+	Symlinks      SymlinkPolicy  // What to do with symbolic links.
+	SkipEmptyDirs bool           // Do not create folders without any transferred file.
+	IncludeHidden bool           // Transfer items whose name starts with a dot.
+	State         TreeStore      // Where the state of the files transferred is kept to skip the unchanged ones on later runs.
+	Conflicts     ConflictPolicy // What to do with files changed on both sides, see State.
+	Folders       []string       // Ids of the only Box folders to download with their content, if any.
+	SkipFolders   []string       // Ids of Box folders not to download.
+	Deletions     DeletionPolicy // Whether to propagate deletions, see State.
+	ArchiveDir    string         // Where DownloadTree moves the local files deleted on Box instead of removing them, if set.
+	ArchiveFolder *Folder        // Where UploadTree moves the Box files deleted locally instead of trashing them, if set.
+}
+
+// folderSelection tells which Box folders DownloadTree descends into,
+// from the Folders and SkipFolders of the options.
+type folderSelection struct {
+	selected  map[string]bool // folders to download with their content
+	ancestors map[string]bool // folders leading to selected ones
+	skipped   map[string]bool
+	inside    map[string]bool // paths of the folders being downloaded
+}
+
+// newFolderSelection returns the selection of the options for a tree
+// rooted at root, looking up where the selected folders are.
+func newFolderSelection(ctx context.Context, box *Box, root string, opts *TreeOptions) (*folderSelection, error) {
+	s := &folderSelection{
+		selected:  map[string]bool{},
+		ancestors: map[string]bool{},
+		skipped:   map[string]bool{},
+		inside:    map[string]bool{},
+	}
+	for _, id := range opts.SkipFolders {
+		s.skipped[id] = true
+	}
+	for _, id := range opts.Folders {
+		s.selected[id] = true
+		folder := &Folder{Id: id}
+		if err := folder.Get(ctx, box, WithFields("path_collection")); err != nil {
+			return nil, err
+		}
+		if folder.PathCollection != nil {
+			for _, e := range folder.PathCollection.Entry {
+				s.ancestors[e.Id] = true
+			}
+		}
+	}
+	if len(s.selected) == 0 || s.selected[root] {
+		s.inside["."] = true
+	}
+	return s, nil
+}
+
+// folder tells whether the folder at p is to be downloaded, and whether
+// it is to be descended into.
+func (s *folderSelection) folder(p, id string) (download, descend bool) {
+	switch {
+	case s.skipped[id]:
+		return false, false
+	case s.inside[path.Dir(p)] || s.selected[id]:
+		s.inside[p] = true
+		return true, true
+	}
+	return false, s.ancestors[id]
+}
+
+// file tells whether the file at p is to be downloaded.
+func (s *folderSelection) file(p string) bool {
+	return s.inside[path.Dir(p)]
+}
+
+// TreeEntry records the decision taken for a single item.
+type TreeEntry struct {
+	Path   string // Slash separated path relative to the root of the tree.
+	Action string // One of the Tree actions.
+	Reason string // Why the item was skipped.
+	Err    error  // Why the item failed.
+
+	// Set for the files downloaded by DownloadTree.
+	Size         int64  // The number of bytes written.
+	ExpectedSha1 string // The sha1 hash of the file according to Box.
+	Sha1         string // The sha1 hash of the content written.
+}
+
+// TreeReport is the result of a tree operation.
+type TreeReport struct {
+	Entries []TreeEntry
+}
+
+// Failed returns the entries that could not be transferred.
+func (r *TreeReport) Failed() []TreeEntry {
+	var failed []TreeEntry
+	for _, e := range r.Entries {
+		if e.Action == TreeFailed {
+			failed = append(failed, e)
+		}
+	}
+	return failed
+}
+
+// Verified reports whether the whole tree was transferred: nothing
+// failed and every file downloaded matched its sha1 hash, e.g. for a
+// backup job to prove integrity.
+func (r *TreeReport) Verified() bool {
+	for _, e := range r.Entries {
+		if e.Action == TreeFailed {
+			return false
+		}
+		if e.Action == TreeDownloaded && (e.ExpectedSha1 == "" || e.Sha1 != e.ExpectedSha1) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *TreeReport) add(p, action, reason string, err error) {
+	r.Entries = append(r.Entries, TreeEntry{Path: p, Action: action, Reason: reason, Err: err})
+}
+
+// treeFolder is a remote folder of the tree which is only created once
+// something is put in it.
+type treeFolder struct {
+	parent *treeFolder
+	name   string
+	rel    string
+	folder *Folder
+}
+
+// get returns the remote folder, creating it and its parents first if
+// needed.
+func (t *treeFolder) get(ctx context.Context, box *Box, report *TreeReport) (*Folder, error) {
+	if t.folder != nil {
+		return t.folder, nil
+	}
+	parent, err := t.parent.get(ctx, box, report)
+	if err != nil {
+		return nil, err
+	}
+	if t.folder, err = parent.CreateOrGet(ctx, box, t.name); err != nil {
+		report.add(t.rel, TreeFailed, "", err)
+		return nil, err
+	}
+	report.add(t.rel, TreeCreated, "", nil)
+	return t.folder, nil
+}
+
+// UploadTree uploads the content of the local directory dir under the
+// folder, creating sub folders as needed. Failures of single items are
+// recorded in the report and do not stop the upload; the returned
+// error is only set when the whole operation had to be aborted. Note
+// that only Id is required apriori.
+func (f *Folder) UploadTree(ctx context.Context, box *Box, dir string, opts *TreeOptions, options ...RequestOption) (*TreeReport, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using UploadTree")
+	}
+	if opts == nil {
+		opts = &TreeOptions{}
+	}
+	if err := opts.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	report := &TreeReport{}
+	root := &treeFolder{folder: f}
+	visited := map[string]bool{}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		visited[real] = true
+	}
+	err := uploadDir(ctx, box, root, dir, "", opts, report, visited)
+	if err == nil && opts.State != nil && (opts.Deletions == DeleteToRemote || opts.Deletions == DeleteBoth) {
+		err = deleteRemote(ctx, box, dir, opts, report)
+	}
+	return report, err
+}
+
+// uploadDir uploads the entries of the local directory dir into the
+// remote folder t. rel is the path of dir relative to the root.
+func uploadDir(ctx context.Context, box *Box, t *treeFolder, dir, rel string, opts *TreeOptions, report *TreeReport, visited map[string]bool) error {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if rel == "" {
+			return err
+		}
+		report.add(rel, TreeFailed, "", err)
+		return nil
+	}
+
+	for _, info := range infos {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		name := info.Name()
+		p := path.Join(rel, name)
+		full := filepath.Join(dir, name)
+
+		if !opts.IncludeHidden && strings.HasPrefix(name, ".") {
+			report.add(p, TreeSkipped, "hidden", nil)
+			continue
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			switch opts.Symlinks {
+			case SymlinkFollow:
+				if info, err = os.Stat(full); err != nil {
+					report.add(p, TreeFailed, "", err)
+					continue
+				}
+			case SymlinkError:
+				err = errors.New("Symbolic link " + full + " while using UploadTree")
+				report.add(p, TreeFailed, "symlink", err)
+				return err
+			default:
+				report.add(p, TreeSkipped, "symlink", nil)
+				continue
+			}
+		}
+
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			report.add(p, TreeSkipped, "special file", nil)
+			continue
+		}
+
+		if !opts.Filter.MatchLocal(p, info) {
+			report.add(p, TreeSkipped, "filtered", nil)
+			continue
+		}
+
+		if info.IsDir() {
+			// Guard against symbolic links looping back up the tree.
+			if real, err := filepath.EvalSymlinks(full); err == nil {
+				if visited[real] {
+					report.add(p, TreeSkipped, "symlink loop", nil)
+					continue
+				}
+				visited[real] = true
+			}
+			sub := &treeFolder{parent: t, name: name, rel: p}
+			if err = uploadDir(ctx, box, sub, full, p, opts, report, visited); err != nil {
+				return err
+			}
+			continue
+		}
+
+		parent, err := t.get(ctx, box, report)
+		if err != nil {
+			report.add(p, TreeFailed, "", err)
+			continue
+		}
+		if opts.State != nil {
+			if st, ok, err := opts.State.Get(p); err != nil {
+				return err
+			} else if ok && st.unchangedLocal(info) {
+				report.add(p, TreeSkipped, "unchanged", nil)
+				continue
+			}
+		}
+		file := File{Name: name}
+		if err = file.UploadFile(ctx, box, full, parent); err != nil {
+			report.add(p, TreeFailed, "", err)
+			continue
+		}
+		report.add(p, TreeUploaded, "", nil)
+		if opts.State != nil {
+			st := TreeFileState{Id: file.Id, ETag: file.ETag, Sha1: file.Sha1, Size: info.Size(), ModTime: info.ModTime()}
+			if err = opts.State.Put(p, st); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !opts.SkipEmptyDirs && rel != "" {
+		t.get(ctx, box, report)
+	}
+	return nil
+}
+
+// ChecksumError is returned when the content downloaded does not match
+// the sha1 hash Box has for the file.
+type ChecksumError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("Checksum mismatch, expected sha1 %s got %s", e.Expected, e.Actual)
+}
+
+// DownloadTree downloads the content of the folder into the local
+// directory dir, creating sub directories as needed and overwriting
+// existing files once the new content is downloaded, so a failure
+// keeps the previous copy. Every file downloaded is checked against
+// its sha1 hash and the report records the hashes and sizes, see
+// TreeReport.Verified. Failures of single items are recorded in the
+// report and do not stop the download; the returned error is only set
+// when the whole operation had to be aborted. Symbolic links do not
+// apply. Note that only Id is required apriori.
+func (f *Folder) DownloadTree(ctx context.Context, box *Box, dir string, opts *TreeOptions, options ...RequestOption) (*TreeReport, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using DownloadTree")
+	}
+	if opts == nil {
+		opts = &TreeOptions{}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	selection, err := newFolderSelection(ctx, box, f.Id, opts)
+	if err != nil {
+		return nil, err
+	}
+	report := &TreeReport{}
+	seen := map[string]string{} // paths of the files found by id
+	walkOpts := &WalkOptions{Filter: opts.Filter}
+	err = f.Walk(ctx, box, walkOpts, func(p string, item *Entity) error {
+		var reason string
+		switch {
+		case !opts.IncludeHidden && strings.HasPrefix(item.Name, "."):
+			reason = "hidden"
+		case item.Name == "." || item.Name == ".." || strings.ContainsAny(item.Name, `/\`):
+			reason = "invalid name"
+		}
+		if reason != "" {
+			report.add(p, TreeSkipped, reason, nil)
+			if item.IsFolder() {
+				return SkipFolder
+			}
+			return nil
+		}
+		if item.IsFolder() {
+			switch download, descend := selection.folder(p, item.Id); {
+			case !descend:
+				report.add(p, TreeSkipped, "not selected", nil)
+				return SkipFolder
+			case !download:
+				// On the way to a selected folder.
+				return nil
+			}
+		} else if !selection.file(p) {
+			return nil
+		} else {
+			seen[item.Id] = p
+		}
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		switch {
+		case item.IsFolder():
+			if opts.SkipEmptyDirs {
+				return nil
+			}
+			if err := os.MkdirAll(full, 0755); err != nil {
+				report.add(p, TreeFailed, "", err)
+				return SkipFolder
+			}
+			report.add(p, TreeCreated, "", nil)
+		case item.IsFile():
+			if opts.State != nil {
+				st, ok, err := opts.State.Get(p)
+				if err != nil {
+					return err
+				}
+				if info, serr := os.Stat(full); ok && serr == nil {
+					localChanged, remoteChanged := !st.unchangedLocal(info), !st.unchangedRemote(item)
+					switch {
+					case !localChanged && !remoteChanged:
+						report.add(p, TreeSkipped, "unchanged", nil)
+						return nil
+					case !localChanged || !remoteChanged:
+						// Changed on one side only, Box wins.
+					case opts.Conflicts == ConflictKeepLocal:
+						report.add(p, TreeSkipped, "conflict", nil)
+						return nil
+					case opts.Conflicts == ConflictFail:
+						report.add(p, TreeFailed, "conflict", errors.New("File "+p+" changed both locally and on Box"))
+						return nil
+					case opts.Conflicts == ConflictKeepBoth:
+						if err = os.Rename(full, conflictName(full, info.ModTime())); err != nil {
+							report.add(p, TreeFailed, "conflict", err)
+							return nil
+						}
+					}
+				}
+			}
+			entry := downloadTreeFile(ctx, box, item, p, full)
+			report.Entries = append(report.Entries, entry)
+			if opts.State != nil && entry.Action == TreeDownloaded {
+				info, err := os.Stat(full)
+				if err != nil {
+					return err
+				}
+				st := TreeFileState{Id: item.Id, ETag: item.ETag, Sha1: entry.Sha1, Size: info.Size(), ModTime: info.ModTime()}
+				if err = opts.State.Put(p, st); err != nil {
+					return err
+				}
+			}
+		default:
+			report.add(p, TreeSkipped, item.Type, nil)
+		}
+		return nil
+	})
+	if err == nil && opts.State != nil && (opts.Deletions == DeleteToLocal || opts.Deletions == DeleteBoth) {
+		err = deleteLocal(ctx, box, f.Id, dir, opts, report, seen)
+	}
+	return report, err
+}
+
+// conflictName returns the name of the conflicted copy of the file at
+// p, dated with t, e.g. "notes (conflicted copy 2024-05-01).txt".
+func conflictName(p string, t time.Time) string {
+	ext := filepath.Ext(p)
+	if ext == p || ext == filepath.Base(p) {
+		ext = ""
+	}
+	return strings.TrimSuffix(p, ext) + " (conflicted copy " + t.Format("2006-01-02") + ")" + ext
+}
+
+// downloadTreeFile downloads the file item to full, hashing it on the
+// way, and returns its report entry. The content goes to a temporary
+// file renamed over full once verified, so that a failed download
+// leaves the previous local copy as it was.
+func downloadTreeFile(ctx context.Context, box *Box, item *Entity, p, full string) TreeEntry {
+	entry := TreeEntry{Path: p, Action: TreeFailed, ExpectedSha1: item.Sha1}
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		entry.Err = err
+		return entry
+	}
+	out, err := ioutil.TempFile(filepath.Dir(full), filepath.Base(full)+".tmp")
+	if err != nil {
+		entry.Err = err
+		return entry
+	}
+	h := sha1.New()
+	file := &File{Id: item.Id}
+	cw := &countingWriter{w: io.MultiWriter(out, h)}
+	err = file.Download(ctx, box, cw)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	entry.Size = cw.n
+	entry.Sha1 = hex.EncodeToString(h.Sum(nil))
+	if err == nil && entry.ExpectedSha1 != "" && entry.Sha1 != entry.ExpectedSha1 {
+		err = &ChecksumError{Expected: entry.ExpectedSha1, Actual: entry.Sha1}
+	}
+	if err == nil {
+		err = replaceFile(out.Name(), full)
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		entry.Err = err
+		return entry
+	}
+	entry.Action = TreeDownloaded
+	return entry
+}
+
+// replaceFile renames the file at tmp over the file at path, giving it
+// the mode of the file it replaces, or else the usual mode of new
+// files.
+func replaceFile(tmp, path string) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.Chmod(tmp, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
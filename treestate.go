@@ -0,0 +1,267 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TreeFileState is what a tree operation last saw of a file it
+// transferred, on both sides.
+type TreeFileState struct {
+	Id      string    `json:"id"`             // The id of the file on Box.
+	ETag    string    `json:"etag,omitempty"` // The etag of the file on Box.
+	Sha1    string    `json:"sha1,omitempty"` // The sha1 hash of the content.
+	Size    int64     `json:"size"`           // The size of the local file.
+	ModTime time.Time `json:"mod_time"`       // The modification time of the local file.
+}
+
+// TreeStore persists the state of the files transferred by the tree
+// operations so that later runs only transfer the files changed since,
+// see TreeOptions.State. Implementations must be safe for concurrent
+// use.
+type TreeStore interface {
+	// Get returns the state of the file at the slash separated path
+	// relative to the root of the tree, or false if it is unknown.
+	Get(p string) (TreeFileState, bool, error)
+	// Put records the state of the file at path.
+	Put(p string, state TreeFileState) error
+	// Delete forgets the file at path.
+	Delete(p string) error
+	// Paths returns the paths of the files known to the store.
+	Paths() ([]string, error)
+}
+
+// FileTreeStore is a TreeStore saving the state as a json file,
+// rewritten after every change.
+type FileTreeStore struct {
+	path  string
+	mu    sync.Mutex
+	files map[string]TreeFileState
+}
+
+// NewFileTreeStore returns a store saving to the file at path, loading
+// the state already saved there if any.
+func NewFileTreeStore(path string) (*FileTreeStore, error) {
+	s := &FileTreeStore{path: path, files: map[string]TreeFileState{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &s.files); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileTreeStore) Get(p string) (TreeFileState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.files[p]
+	return state, ok, nil
+}
+
+func (s *FileTreeStore) Put(p string, state TreeFileState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[p] = state
+	return s.save()
+}
+
+func (s *FileTreeStore) Delete(p string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.files[p]; !ok {
+		return nil
+	}
+	delete(s.files, p)
+	return s.save()
+}
+
+// Paths returns the paths of the files known to the store, sorted.
+func (s *FileTreeStore) Paths() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := make([]string, 0, len(s.files))
+	for p := range s.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// save writes the state to a temporary file renamed over the store
+// file, so that an interruption never leaves it half written. s.mu must
+// be held.
+func (s *FileTreeStore) save() error {
+	data, err := json.Marshal(s.files)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file renamed over the file
+// at path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// unchangedLocal reports whether the local file is still the one the
+// state was recorded for.
+func (st *TreeFileState) unchangedLocal(info os.FileInfo) bool {
+	return info.Size() == st.Size && info.ModTime().Equal(st.ModTime)
+}
+
+// unchangedRemote reports whether the file on Box is still the one the
+// state was recorded for.
+func (st *TreeFileState) unchangedRemote(item *Entity) bool {
+	if item.Id != st.Id {
+		return false
+	}
+	if item.Sha1 != "" && st.Sha1 != "" {
+		return item.Sha1 == st.Sha1
+	}
+	return item.ETag != "" && item.ETag == st.ETag
+}
+
+// deleteRemote deletes from Box the files of the state which no longer
+// exist under the local directory dir, or moves them to the archive
+// folder of the options.
+func deleteRemote(ctx context.Context, box *Box, dir string, opts *TreeOptions, report *TreeReport) error {
+	paths, err := opts.State.Paths()
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if _, err = os.Lstat(filepath.Join(dir, filepath.FromSlash(p))); !os.IsNotExist(err) {
+			continue
+		}
+		st, ok, err := opts.State.Get(p)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		file := &File{Id: st.Id}
+		if opts.ArchiveFolder != nil {
+			err = file.Move(ctx, box, opts.ArchiveFolder)
+		} else {
+			err = file.Delete(ctx, box)
+		}
+		if err != nil && !errors.Is(err, NOT_FOUND) {
+			report.add(p, TreeFailed, "", err)
+			continue
+		}
+		report.add(p, TreeDeleted, "", nil)
+		if err = opts.State.Delete(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteLocal deletes from the local directory dir the files of the
+// state which are no longer in the Box folder root, or moves them to
+// the archive directory of the options. seen holds the paths of the
+// files found in the folder by id, other files are looked up to tell
+// deleted ones from ones left out by the options.
+func deleteLocal(ctx context.Context, box *Box, root, dir string, opts *TreeOptions, report *TreeReport, seen map[string]string) error {
+	paths, err := opts.State.Paths()
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		st, ok, err := opts.State.Get(p)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		gone, err := goneFrom(ctx, box, root, p, st.Id, seen)
+		if err != nil {
+			report.add(p, TreeFailed, "", err)
+			continue
+		}
+		if !gone {
+			continue
+		}
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if opts.ArchiveDir != "" {
+			archived := filepath.Join(opts.ArchiveDir, filepath.FromSlash(p))
+			if err = os.MkdirAll(filepath.Dir(archived), 0755); err == nil {
+				err = os.Rename(full, archived)
+			}
+		} else {
+			err = os.Remove(full)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			report.add(p, TreeFailed, "", err)
+			continue
+		}
+		report.add(p, TreeDeleted, "", nil)
+		if err = opts.State.Delete(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goneFrom reports whether the file id recorded in the state at p is no
+// longer in the Box folder root: deleted, trashed, moved out of it or
+// found at another path.
+func goneFrom(ctx context.Context, box *Box, root, p, id string, seen map[string]string) (bool, error) {
+	if found, ok := seen[id]; ok {
+		// Moved or renamed inside the folder.
+		return found != p, nil
+	}
+	file := &File{Id: id}
+	err := file.Get(ctx, box, WithFields("item_status", "path_collection"))
+	if errors.Is(err, NOT_FOUND) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if file.ItemStatus != "" && file.ItemStatus != "active" {
+		return true, nil
+	}
+	if file.PathCollection != nil {
+		for _, e := range file.PathCollection.Entry {
+			if e.Id == root {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,423 @@
+package box
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Uploader sends the content of new files to Box. File.Upload checks
+// the file and the parent before handing them over, and uses the
+// Uploader of the box if set, else one chosen by size, so tests and
+// other transports can be plugged in.
+type Uploader interface {
+	// Upload uploads the content of r, size bytes or -1 if unknown, as
+	// the file named f.Name in parent and populates f.
+	Upload(ctx context.Context, box *Box, f *File, r io.Reader, size int64, parent *Folder) error
+}
+
+// MultipartUploader uploads files in a single multipart request. Files
+// up to the RetryUploadSize of the box are retried on transport errors,
+// server errors and rate limits.
+type MultipartUploader struct{}
+
+// ChunkedUploader uploads files part by part through an upload
+// session, see UploadSession, several parts at once as set by the
+// UploadWorkers of the box. Box only accepts sessions for files of 20MB
+// or more, whose size must be known.
+type ChunkedUploader struct{}
+
+// defaultChunkedUploadSize is the size from which files are uploaded in
+// chunks by default, as recommended by Box.
+const defaultChunkedUploadSize = 50 << 20
+
+// uploader returns the uploader of the files of the given size.
+func (box *Box) uploader(size int64) Uploader {
+	if box.Uploader != nil {
+		return box.Uploader
+	}
+	if box.ChunkedUploadSize > 0 && size >= box.ChunkedUploadSize {
+		return ChunkedUploader{}
+	}
+	return MultipartUploader{}
+}
+
+// readerSize returns the number of bytes left in r, or -1 if unknown.
+func readerSize(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+func (MultipartUploader) Upload(ctx context.Context, box *Box, f *File, reader io.Reader, size int64, parent *Folder) error {
+	var err error
+
+	contentType := f.ContentType
+	if contentType == "" {
+		if reader, contentType, err = detectContentType(f.Name, reader); err != nil {
+			return err
+		}
+	}
+
+	// Buffer small files so that the upload can be retried
+	var buf []byte
+	small := false
+	if box.RetryUploadSize > 0 {
+		if buf, err = ioutil.ReadAll(io.LimitReader(reader, box.RetryUploadSize+1)); err != nil {
+			return err
+		}
+		small = int64(len(buf)) <= box.RetryUploadSize
+		if small {
+			size = int64(len(buf))
+		}
+		reader = io.MultiReader(bytes.NewReader(buf), reader)
+	}
+
+	var respBody []byte
+	var status, attempt int
+	progress := uploadProgress(ctx)
+	spanCtx, end := startSpan(ctx, "POST", "files/content")
+	for ; ; attempt++ {
+		r := reader
+		if small {
+			r = bytes.NewReader(buf)
+		}
+		if progress != nil {
+			r = &progressReader{r: r, total: size, fn: progress}
+		}
+		var retryAfter string
+		respBody, status, retryAfter, err = box.postUpload(spanCtx, f.Name, contentType, r, parent.Id)
+		if !small || !uploadRetryable(status) || attempt >= box.MaxRetries {
+			break
+		}
+		if err = sleep(ctx, box.backoff(attempt, retryAfter)); err != nil {
+			break
+		}
+	}
+	end(status, attempt, err)
+	if err != nil {
+		return err
+	}
+	return box.decodeUploaded(respBody, f)
+}
+
+// uploadRetryable checks if a buffered upload answered with the status,
+// zero if it failed before getting one, can be sent again. Unlike other
+// POSTs, uploads are retried on transport and server errors: Box fails
+// a second upload of the same name with a conflict instead of creating
+// a duplicate.
+func uploadRetryable(status int) bool {
+	return status == 0 || status == TOO_MANY_REQUESTS.StatusCode || serverError(status)
+}
+
+// decodeUploaded populates f from the response to an upload.
+func (box *Box) decodeUploaded(respBody []byte, f *File) error {
+	// All because of weird box's return format of response body
+	var m map[string]json.RawMessage
+	err := json.Unmarshal(respBody, &m)
+	if err != nil {
+		return err
+	}
+	var fs []json.RawMessage
+	err = json.Unmarshal(m["entries"], &fs)
+	if err != nil {
+		return err
+	}
+	if len(fs) != 1 {
+		return errors.New("Not enough returned argument")
+	}
+	return box.decode(fs[0], f)
+}
+
+func (ChunkedUploader) Upload(ctx context.Context, box *Box, f *File, r io.Reader, size int64, parent *Folder) error {
+	if size < 0 {
+		return errors.New("Unknown size while using chunked upload")
+	}
+	store := box.UploadSessions
+	key := fmt.Sprintf("%s/%s/%d", parent.Id, f.Name, size)
+	s, done, err := box.uploadSession(ctx, store, key, parent, f.Name, size)
+	if err != nil {
+		return err
+	}
+	err = s.uploadParts(ctx, box, f, r, size, done)
+	if store != nil && (err == nil || !resumable(err)) {
+		if derr := store.Delete(key); err == nil {
+			err = derr
+		}
+	}
+	if err != nil && (store == nil || !resumable(err)) {
+		// Left behind sessions expire, see AbortStaleUploadSessions.
+		s.Abort(ctx, box)
+	}
+	return err
+}
+
+// uploadSession returns the session recorded in store for key with the
+// parts it already has, or else a new session recorded in store.
+func (box *Box) uploadSession(ctx context.Context, store UploadSessionStore, key string, parent *Folder, name string, size int64) (*UploadSession, map[int64]UploadPart, error) {
+	if store != nil {
+		id, err := store.Get(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id != "" {
+			s := &UploadSession{Id: id}
+			err = s.Get(ctx, box)
+			if err == nil {
+				parts, err := s.Parts(ctx, box)
+				if err != nil {
+					return nil, nil, err
+				}
+				done := make(map[int64]UploadPart, len(parts))
+				for _, p := range parts {
+					done[p.Offset] = p
+				}
+				return s, done, nil
+			}
+			// Start over when the session expired.
+			if !errors.Is(err, NOT_FOUND) {
+				return nil, nil, err
+			}
+		}
+	}
+	s := &UploadSession{}
+	if err := s.Create(ctx, box, parent, name, size); err != nil {
+		return nil, nil, err
+	}
+	if store != nil {
+		if err := store.Put(key, s.Id); err != nil {
+			s.Abort(ctx, box)
+			return nil, nil, err
+		}
+	}
+	return s, nil, nil
+}
+
+// errContentChanged is returned when resuming an upload whose content
+// differs from the parts uploaded before.
+var errContentChanged = errors.New("Content changed since the upload session started")
+
+// resumable checks if the upload session can still be resumed after
+// the upload failed with err.
+func resumable(err error) bool {
+	var boxErr *BoxError
+	if errors.As(err, &boxErr) {
+		// Parts are sent again with PUT, which is idempotent.
+		return retryable("PUT", boxErr.StatusCode)
+	}
+	return err != errContentChanged
+}
+
+// defaultUploadWorkers is the number of parts uploaded at once unless
+// the box says otherwise.
+const defaultUploadWorkers = 4
+
+// uploadParts uploads the size bytes of r in parts, up to UploadWorkers
+// of the box at once, and commits the session into f. The parts are
+// read and hashed in order, the ones in done, by offset, are only
+// checked.
+func (s *UploadSession) uploadParts(ctx context.Context, box *Box, f *File, r io.Reader, size int64, done map[int64]UploadPart) error {
+	if s.PartSize <= 0 {
+		return errors.New("Empty part size while using chunked upload")
+	}
+	workers := box.UploadWorkers
+	if workers < 1 {
+		workers = defaultUploadWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var firstErr error
+	var sent int64
+	progress := uploadProgress(ctx)
+	report := func(n int64) {
+		if progress == nil {
+			return
+		}
+		mu.Lock()
+		sent += n
+		progress(sent, size)
+		mu.Unlock()
+	}
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+
+	// The buffers of the parts in flight, reused once uploaded.
+	buffers := make(chan []byte, workers)
+	for i := 0; i < workers; i++ {
+		buffers <- nil
+	}
+
+	hash := sha1.New()
+	parts := make([]UploadPart, (size+s.PartSize-1)/s.PartSize)
+	var wg sync.WaitGroup
+	for i, offset := 0, int64(0); offset < size; i++ {
+		var buf []byte
+		select {
+		case buf = <-buffers:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			fail(ctx.Err())
+			break
+		}
+		n := s.PartSize
+		if size-offset < n {
+			n = size - offset
+		}
+		if int64(cap(buf)) < n {
+			buf = make([]byte, s.PartSize)
+		}
+		buf = buf[:n]
+		if _, err := io.ReadFull(r, buf); err != nil {
+			fail(err)
+			break
+		}
+		hash.Write(buf)
+
+		if part, ok := done[offset]; ok {
+			if sum := sha1.Sum(buf); part.Sha1 != "" && part.Sha1 != hex.EncodeToString(sum[:]) {
+				fail(errContentChanged)
+				break
+			}
+			parts[i] = part
+			buffers <- buf
+			report(n)
+			offset += n
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, offset int64, buf []byte) {
+			defer wg.Done()
+			part, err := s.UploadPart(ctx, box, offset, size, buf)
+			if err != nil {
+				fail(err)
+			} else {
+				parts[i] = *part
+				report(int64(len(buf)))
+			}
+			buffers <- buf
+		}(i, offset, buf)
+		offset += n
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return s.Commit(ctx, box, parts, hash.Sum(nil), f)
+}
+
+// UploadPart is a part of a file uploaded through an upload session.
+type UploadPart struct {
+	PartId string `json:"part_id,omitempty"` // The id of the part.
+	Offset int64  `json:"offset"`            // The offset of the part in the file.
+	Size   int64  `json:"size,omitempty"`    // The size of the part in bytes.
+	Sha1   string `json:"sha1,omitempty"`    // The sha1 hash of the part.
+}
+
+// Create creates an upload session for a file of the given name and
+// size in parent. The session is populated after the call. Note that
+// only Id of the parent folder is required apriori.
+func (s *UploadSession) Create(ctx context.Context, box *Box, parent *Folder, name string, size int64, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if parent.Id == "" {
+		return errors.New("Empty parent id while using Create")
+	}
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"folder_id": parent.Id,
+		"file_name": name,
+		"file_size": size,
+	})
+	body, err := box.doRequestAt(ctx, box.APIUPLOADURL, "POST", "files/upload_sessions", nil, nil, reqBody)
+	if err != nil {
+		return err
+	}
+	return box.decode(body, s)
+}
+
+// UploadPart uploads data as the part of the file at the given offset,
+// total being the size of the file. Parts are retried on transient
+// failures. Note that only Id is required apriori.
+func (s *UploadSession) UploadPart(ctx context.Context, box *Box, offset, total int64, data []byte, options ...RequestOption) (*UploadPart, error) {
+	ctx = withCall(ctx, options)
+	if s.Id == "" {
+		return nil, errors.New("Empty id while using UploadPart")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("Empty part while using UploadPart")
+	}
+	sum := sha1.Sum(data)
+	header := http.Header{
+		"Content-Type":  {"application/octet-stream"},
+		"Content-Range": {fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(data))-1, total)},
+		"Digest":        {"sha=" + base64.StdEncoding.EncodeToString(sum[:])},
+	}
+	rawurl := fmt.Sprintf("files/upload_sessions/%s", s.Id)
+	body, err := box.doRequestAt(ctx, box.APIUPLOADURL, "PUT", rawurl, nil, header, data)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Part UploadPart `json:"part"`
+	}
+	err = box.decode(body, &resp)
+	return &resp.Part, err
+}
+
+// Commit completes the upload session with the uploaded parts and the
+// sha1 hash of the whole file, and populates f with the uploaded file.
+// It waits while Box is still processing the parts. Note that only Id
+// is required apriori.
+func (s *UploadSession) Commit(ctx context.Context, box *Box, parts []UploadPart, sha1 []byte, f *File, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if s.Id == "" {
+		return errors.New("Empty id while using Commit")
+	}
+	reqBody, _ := json.Marshal(map[string]interface{}{"parts": parts})
+	header := http.Header{"Digest": {"sha=" + base64.StdEncoding.EncodeToString(sha1)}}
+	rawurl := fmt.Sprintf("files/upload_sessions/%s/commit", s.Id)
+	for {
+		var info ResponseInfo
+		body, err := box.doRequestAt(recordingResponses(ctx, &info), box.APIUPLOADURL, "POST", rawurl, nil, header, reqBody)
+		if err != nil {
+			return err
+		}
+		if info.StatusCode() != ACCEPTED.StatusCode {
+			return box.decodeUploaded(body, f)
+		}
+		if err = sleep(ctx, box.pollDelay(info.Header().Get("Retry-After"))); err != nil {
+			return err
+		}
+	}
+}
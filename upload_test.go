@@ -0,0 +1,124 @@
+package box
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// drop is the failure of a flakyUpload closing the connection without
+// answering.
+const drop = 0
+
+// flakyUpload answers the uploads it gets with the statuses of fails in
+// turn, then accepts them, recording the content of each attempt.
+type flakyUpload struct {
+	mu       sync.Mutex
+	fails    []int
+	attempts []string
+	onFail   func() // called before answering with a failure, if set
+}
+
+// count returns the number of attempts received.
+func (u *flakyUpload) count() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.attempts)
+}
+
+func (u *flakyUpload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var content string
+	if part, _, err := r.FormFile("filename"); err == nil {
+		b, _ := ioutil.ReadAll(part)
+		content = string(b)
+	}
+	u.mu.Lock()
+	n := len(u.attempts)
+	u.attempts = append(u.attempts, content)
+	u.mu.Unlock()
+
+	if n < len(u.fails) {
+		if u.onFail != nil {
+			u.onFail()
+		}
+		if u.fails[n] == drop {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Header().Set("Retry-After", "0")
+		http.Error(w, "{}", u.fails[n])
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, `{"total_count":1,"entries":[{"type":"file","id":"1","name":"a.txt","size":%d}]}`, len(content))
+}
+
+func TestUploadRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		fails    []int
+		buffered bool
+		attempts int
+		ok       bool
+	}{
+		{"no failure", nil, true, 1, true},
+		{"dropped connection", []int{drop}, true, 2, true},
+		{"server errors", []int{500, 502, 503}, true, 4, true},
+		{"rate limited", []int{429}, true, 2, true},
+		{"mixed failures", []int{drop, 503, drop}, true, 4, true},
+		{"too many failures", []int{503, 503, 503, 503}, true, 4, false},
+		{"client error", []int{400}, true, 1, false},
+		{"streamed", []int{503}, false, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := &flakyUpload{fails: tt.fails}
+			b := newTestBox(t, srv)
+			if !tt.buffered {
+				b.RetryUploadSize = 0
+			}
+			f, err := b.Files.Upload(context.Background(), "0", "a.txt", strings.NewReader("content"))
+			if n := srv.count(); n != tt.attempts {
+				t.Errorf("sent %d attempts, want %d", n, tt.attempts)
+			}
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("upload succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("upload failed: %v", err)
+			}
+			if f.Size != int64(len("content")) {
+				t.Errorf("uploaded %d bytes, want %d", f.Size, len("content"))
+			}
+			for i, content := range srv.attempts {
+				if content != "content" && tt.fails[i] != drop {
+					t.Errorf("attempt %d sent %q, want the whole content", i, content)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := &flakyUpload{fails: []int{503, 503}, onFail: cancel}
+	b := newTestBox(t, srv)
+	_, err := b.Files.Upload(ctx, "0", "a.txt", strings.NewReader("content"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("upload error %v, want it cancelled", err)
+	}
+	if n := srv.count(); n != 1 {
+		t.Errorf("sent %d attempts after the cancellation, want 1", n)
+	}
+}
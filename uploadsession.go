@@ -0,0 +1,192 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// uploadSessionLifetime is how long Box keeps an upload session before
+// expiring it.
+const uploadSessionLifetime = 7 * 24 * time.Hour
+
+// UploadSession is a chunked upload in progress.
+type UploadSession struct {
+	Id                string            `json:"id,omitempty"`                  // The id of the upload session.
+	Type              string            `json:"type,omitempty"`                // Type of the object, always upload_session.
+	TotalParts        int               `json:"total_parts,omitempty"`         // The number of parts of the file.
+	PartSize          int64             `json:"part_size,omitempty"`           // The size of every part but the last one.
+	NumPartsProcessed int               `json:"num_parts_processed,omitempty"` // The number of parts uploaded so far.
+	SessionExpiresAt  *BoxTime          `json:"session_expires_at,omitempty"`  // When the session expires.
+	SessionEndpoints  map[string]string `json:"session_endpoints,omitempty"`   // The urls of the operations on the session.
+}
+
+// Get populates the fields of the upload session. Note that only Id is
+// required apriori.
+func (s *UploadSession) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if s.Id == "" {
+		return errors.New("Empty id while using Get")
+	}
+	rawurl := fmt.Sprintf("files/upload_sessions/%s", s.Id)
+	body, err := box.doRequestAt(ctx, box.APIUPLOADURL, "GET", rawurl, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return box.decode(body, s)
+}
+
+// Abort aborts the upload session, discarding the parts uploaded so
+// far. Note that only Id is required apriori.
+func (s *UploadSession) Abort(ctx context.Context, box *Box, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if s.Id == "" {
+		return errors.New("Empty id while using Abort")
+	}
+	rawurl := fmt.Sprintf("files/upload_sessions/%s", s.Id)
+	_, err := box.doRequestAt(ctx, box.APIUPLOADURL, "DELETE", rawurl, nil, nil, nil)
+	return err
+}
+
+// Parts returns the parts uploaded so far, e.g. to resume the upload
+// in another process. Note that only Id is required apriori.
+func (s *UploadSession) Parts(ctx context.Context, box *Box, options ...RequestOption) ([]UploadPart, error) {
+	ctx = withCall(ctx, options)
+	if s.Id == "" {
+		return nil, errors.New("Empty id while using Parts")
+	}
+	rawurl := fmt.Sprintf("files/upload_sessions/%s/parts", s.Id)
+	var parts []UploadPart
+	for {
+		params := url.Values{"offset": {strconv.Itoa(len(parts))}, "limit": {"1000"}}
+		body, err := box.doRequestAt(ctx, box.APIUPLOADURL, "GET", rawurl, &params, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Entries    []UploadPart `json:"entries,omitempty"`
+			TotalCount int          `json:"total_count,omitempty"`
+		}
+		if err = box.decode(body, &page); err != nil {
+			return nil, err
+		}
+		parts = append(parts, page.Entries...)
+		if len(page.Entries) == 0 || len(parts) >= page.TotalCount {
+			return parts, nil
+		}
+	}
+}
+
+// CreatedAt returns when the session was created, derived from its
+// expiry as Box does not return it. It is the zero time when the
+// session is not populated.
+func (s *UploadSession) CreatedAt() time.Time {
+	if s.SessionExpiresAt == nil {
+		return time.Time{}
+	}
+	return time.Time(*s.SessionExpiresAt).Add(-uploadSessionLifetime)
+}
+
+// AbortStaleUploadSessions is a janitor aborting the upload sessions
+// among the given ones created more than maxAge ago, e.g. left behind
+// by crashed uploaders. Box cannot list the sessions of a user, so the
+// ids are the ones recorded by the uploaders. Sessions already gone are
+// skipped. It returns the ids of the sessions aborted.
+func (box *Box) AbortStaleUploadSessions(ctx context.Context, ids []string, maxAge time.Duration, options ...RequestOption) ([]string, error) {
+	ctx = withCall(ctx, options)
+	var aborted []string
+	for _, id := range ids {
+		s := &UploadSession{Id: id}
+		err := s.Get(ctx, box)
+		if errors.Is(err, NOT_FOUND) {
+			continue
+		}
+		if err != nil {
+			return aborted, err
+		}
+		if time.Since(s.CreatedAt()) < maxAge {
+			continue
+		}
+		if err = s.Abort(ctx, box); err != nil && !errors.Is(err, NOT_FOUND) {
+			return aborted, err
+		}
+		aborted = append(aborted, id)
+	}
+	return aborted, nil
+}
+
+// UploadSessionStore records the ids of the upload sessions in progress
+// so that interrupted chunked uploads can be resumed by another
+// process, see Box.UploadSessions. The keys identify the uploads.
+type UploadSessionStore interface {
+	// Get returns the id of the session of the upload, "" if none.
+	Get(key string) (string, error)
+	// Put records the id of the session of the upload.
+	Put(key, id string) error
+	// Delete forgets the session of the upload, done or abandoned.
+	Delete(key string) error
+}
+
+// FileUploadSessionStore is an UploadSessionStore saving the sessions
+// as a json file, safe for concurrent use.
+type FileUploadSessionStore struct {
+	path     string
+	mu       sync.Mutex
+	sessions map[string]string
+}
+
+// NewFileUploadSessionStore returns a store saving to the file at path,
+// loading the sessions already saved there if any.
+func NewFileUploadSessionStore(path string) (*FileUploadSessionStore, error) {
+	s := &FileUploadSessionStore{path: path, sessions: map[string]string{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &s.sessions); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileUploadSessionStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[key], nil
+}
+
+func (s *FileUploadSessionStore) Put(key, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[key] = id
+	return s.save()
+}
+
+func (s *FileUploadSessionStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[key]; !ok {
+		return nil
+	}
+	delete(s.sessions, key)
+	return s.save()
+}
+
+// save writes the sessions to the store file. s.mu must be held.
+func (s *FileUploadSessionStore) save() error {
+	data, err := json.Marshal(s.sessions)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
@@ -0,0 +1,173 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type User struct {
+	Id                         string   `json:"id,omitempty"`                            // Box’s unique string identifying this user.
+	Type                       string   `json:"type,omitempty"`                          // Type of the object, always user.
+	Name                       string   `json:"name,omitempty"`                          // The name of this user.
+	Login                      string   `json:"login,omitempty"`                         // The email address this user uses to login.
+	CreatedAt                  *BoxTime `json:"created_at,omitempty"`                    // When this user was created.
+	ModifiedAt                 *BoxTime `json:"modified_at,omitempty"`                   // When this user was last updated.
+	Language                   string   `json:"language,omitempty"`                      // The language of this user.
+	Timezone                   string   `json:"timezone,omitempty"`                      // The timezone of this user.
+	SpaceAmount                int64    `json:"space_amount,omitempty"`                  // The user’s total available space amount in bytes.
+	SpaceUsed                  int64    `json:"space_used,omitempty"`                    // The amount of space in use by the user.
+	MaxUploadSize              int64    `json:"max_upload_size,omitempty"`               // The maximum individual file size in bytes this user can have.
+	Status                     string   `json:"status,omitempty"`                        // Can be active, inactive, cannot_delete_edit, or cannot_delete_edit_upload.
+	JobTitle                   string   `json:"job_title,omitempty"`                     // The user’s job title.
+	Phone                      string   `json:"phone,omitempty"`                         // The user’s phone number.
+	Address                    string   `json:"address,omitempty"`                       // The user’s address.
+	AvatarUrl                  string   `json:"avatar_url,omitempty"`                    // URL of this user’s avatar image.
+	IsPlatformAccessOnly       bool     `json:"is_platform_access_only,omitempty"`       // Whether this is an app user of Box Platform.
+	ExternalAppUserId          string   `json:"external_app_user_id,omitempty"`          // The id of this app user in the application.
+	Enterprise                 *Entity  `json:"enterprise,omitempty"`                    // The enterprise of this user, if requested.
+	IsExternalCollabRestricted bool     `json:"is_external_collab_restricted,omitempty"` // Whether this user cannot collaborate outside the enterprise, if requested.
+	CanSeeManagedUsers         bool     `json:"can_see_managed_users,omitempty"`         // Whether this user can see the other users of the enterprise, if requested.
+}
+
+// Get populates the fields of the user struct, only the ones of
+// WithFields if given. Note that only Id is required apriori.
+//
+// Deprecated: Use box.Users.Get.
+func (u *User) Get(ctx context.Context, box *Box, options ...RequestOption) error {
+	return (&userService{box}).get(ctx, u, options)
+}
+
+// get populates u, see User.Get.
+func (s *userService) get(ctx context.Context, u *User, options []RequestOption) error {
+	box := s.box
+	ctx = withCall(ctx, options)
+	if u.Id == "" {
+		return errors.New("Empty id while using Get")
+	}
+	rawurl := fmt.Sprintf("users/%s", u.Id)
+	body, err := box.doRequest(ctx, "GET", rawurl, nil, nil)
+	if err != nil {
+		return err
+	}
+	return box.decode(body, u)
+}
+
+// CurrentUser returns the user the box is authenticated as, with only
+// the fields of WithFields if given, e.g. "enterprise" and
+// "is_external_collab_restricted" to see the policies applying to them.
+func (box *Box) CurrentUser(ctx context.Context, options ...RequestOption) (*User, error) {
+	u := &User{Id: "me"}
+	if err := u.Get(ctx, box, options...); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// CreateAppUser creates an app user of Box Platform with the given
+// name, linked to the user of the application with id externalId if
+// not empty. The box must be authenticated as the service account of
+// the enterprise.
+func (box *Box) CreateAppUser(ctx context.Context, name, externalId string, options ...RequestOption) (*User, error) {
+	ctx = withCall(ctx, options)
+	if name == "" {
+		return nil, errors.New("Empty name while using CreateAppUser")
+	}
+	user := &User{Name: name, IsPlatformAccessOnly: true, ExternalAppUserId: externalId}
+	reqBody, _ := json.Marshal(user)
+	body, err := box.doRequest(ctx, "POST", "users", nil, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	user = &User{}
+	err = box.decode(body, user)
+	return user, err
+}
+
+// FindAppUser returns the app user linked to the user of the
+// application with id externalId. It fails with a NotFoundError if
+// there is none.
+func (box *Box) FindAppUser(ctx context.Context, externalId string, options ...RequestOption) (*User, error) {
+	ctx = withCall(ctx, options)
+	if externalId == "" {
+		return nil, errors.New("Empty external id while using FindAppUser")
+	}
+	params := &url.Values{"external_app_user_id": {externalId}}
+	body, err := box.doRequest(ctx, "GET", "users", params, nil)
+	if err != nil {
+		return nil, err
+	}
+	var users ListResult[User]
+	if err = box.decode(body, &users); err != nil {
+		return nil, err
+	}
+	if len(users.Entries) == 0 {
+		return nil, &NotFoundError{&BoxError{StatusCode: 404, Message: "No app user with external id " + externalId}}
+	}
+	return &users.Entries[0], nil
+}
+
+// FindUserByLogin returns the user of the enterprise, or the external
+// user, whose login is the given email address, ignoring case. It
+// fails with a NotFoundError when there is none, e.g. before inviting
+// the address to a collaboration instead.
+func (box *Box) FindUserByLogin(ctx context.Context, login string, options ...RequestOption) (*User, error) {
+	ctx = withCall(ctx, options)
+	if login == "" {
+		return nil, errors.New("Empty login while using FindUserByLogin")
+	}
+	// The filter also matches the start of names and logins.
+	params := url.Values{"filter_term": {login}, "user_type": {"all"}, "usemarker": {"true"}}
+	var found *User
+	errFound := errors.New("found")
+	err := box.listAll(ctx, "users", params, nil, func(entries json.RawMessage) error {
+		var page []User
+		if err := box.decode(entries, &page); err != nil {
+			return err
+		}
+		for i := range page {
+			if strings.EqualFold(page[i].Login, login) {
+				found = &page[i]
+				return errFound
+			}
+		}
+		return nil
+	})
+	if found != nil {
+		return found, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, &NotFoundError{&BoxError{StatusCode: 404, Message: "No user with login " + login}}
+}
+
+// ForUser returns a new box authenticated as the given user, such as
+// an app user, with the credentials of the box. Only boxes created by
+// NewBoxJWT or NewBoxCCG and their variants can do so.
+func (box *Box) ForUser(userId string) (*Box, error) {
+	if userId == "" {
+		return nil, errors.New("Empty user id while using ForUser")
+	}
+	box.mu.RLock()
+	newUserBox := box.userBox
+	base := box.baseClient
+	box.mu.RUnlock()
+	if newUserBox == nil {
+		return nil, errors.New("Box was not created with JWT or CCG credentials while using ForUser")
+	}
+	user, err := newUserBox(userId)
+	if err != nil {
+		return nil, err
+	}
+	user.APIURL = box.APIURL
+	user.APIUPLOADURL = box.APIUPLOADURL
+	user.AuthURL = box.AuthURL
+	user.TokenURL = box.TokenURL
+	user.RevokeURL = box.RevokeURL
+	user.SetHTTPClient(base)
+	return user, nil
+}
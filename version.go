@@ -0,0 +1,65 @@
+package box
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// The api versions of the endpoints served under a Box-Version other
+// than the default one. They are kept in one place so that moving to a
+// new version of an endpoint is a deliberate change.
+const (
+	hubsVersion        = "2025.0" // hubs and hub items
+	shieldListsVersion = "2025.0" // shield lists
+)
+
+// agent is the name and version of the package sent to Box, the
+// version being the one of the module when known.
+var agent = "go-box/" + moduleVersion()
+
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, m := range info.Deps {
+			if m.Path == "github.com/satvikc/go-box" && m.Version != "" {
+				return m.Version
+			}
+		}
+	}
+	return "devel"
+}
+
+// setAgentHeaders sets the User-Agent and X-Box-UA headers identifying
+// the package and the application, unless the request already sets
+// them.
+func (box *Box) setAgentHeaders(r *http.Request) {
+	ua := agent
+	if box.UserAgent != "" {
+		ua += " " + box.UserAgent
+	}
+	if r.Header.Get("User-Agent") == "" {
+		r.Header.Set("User-Agent", ua)
+	}
+	if r.Header.Get("X-Box-UA") == "" {
+		v := "agent=" + agent + "; env=Go/" + strings.TrimPrefix(runtime.Version(), "go")
+		if box.UserAgent != "" {
+			v += "; app=" + box.UserAgent
+		}
+		r.Header.Set("X-Box-UA", v)
+	}
+}
+
+// setVersionHeaders sets the Box-Version of the box and its extra
+// headers on the request, unless the request already sets them, e.g.
+// for an endpoint pinned to its own version.
+func (box *Box) setVersionHeaders(r *http.Request) {
+	if box.APIVersion != "" && r.Header.Get("Box-Version") == "" {
+		r.Header.Set("Box-Version", box.APIVersion)
+	}
+	for k, v := range box.Header {
+		if _, ok := r.Header[k]; !ok {
+			r.Header[k] = append([]string(nil), v...)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+)
+
+// SkipFolder can be returned by a WalkFunc to skip the content of the
+// folder it was called with.
+var SkipFolder = errors.New("skip this folder")
+
+// WalkFunc is called by Walk for every item found, with the slash
+// separated path of the item relative to the walked folder.
+type WalkFunc func(p string, item *Entity) error
+
+// WalkOptions configures Walk. The zero value lists one folder at a
+// time without rate limiting.
+type WalkOptions struct {
+	Filter            *Filter    // Rules selecting the items passed to the WalkFunc.
+	Workers           int        // Number of folders listed concurrently.
+	RequestsPerSecond float64    // Maximum rate of listing requests. Zero means no limit.
+	Store             CrawlStore // Where the progress is saved to resume an interrupted walk.
+}
+
+// itemFields are the fields requested for the items of a walk.
+const itemFields = "type,id,sequence_id,etag,name,size,sha1,modified_at"
+
+// Walk walks the tree rooted at the folder, calling fn for every item
+// passing the filter of opts. Sibling folders are listed concurrently
+// by up to opts.Workers goroutines, so items are not visited in any
+// particular order, but calls to fn are serialized. Folders excluded by
+// the filter are not descended into. The walk stops at the first error
+// returned by fn or by a listing, and that error is returned. Note
+// that only Id is required apriori.
+//
+// With a Store the walk is restartable: the folders left to list and
+// the position inside them are saved as the walk goes, and a walk
+// started with a store holding pending folders resumes from them
+// instead of the root. Items of the page being processed when the walk
+// was interrupted are passed to fn again on resume.
+func (f *Folder) Walk(ctx context.Context, box *Box, opts *WalkOptions, fn WalkFunc, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return errors.New("Empty id while using Walk")
+	}
+	if opts == nil {
+		opts = &WalkOptions{}
+	}
+	if err := opts.Filter.Validate(); err != nil {
+		return err
+	}
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	start := []CrawlFolder{{Id: f.Id}}
+	if opts.Store != nil {
+		pending, err := opts.Store.Pending()
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			start = pending
+		} else if err = opts.Store.Add(start); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var throttle <-chan time.Time
+	if opts.RequestsPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RequestsPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, workers)
+		mu       sync.Mutex // serializes fn and guards firstErr
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+
+	var visit func(folder CrawlFolder)
+	// page handles a single page of the items of folder, returning the
+	// sub folders to descend into.
+	page := func(folder CrawlFolder, items []Entity) ([]CrawlFolder, error) {
+		var subs []CrawlFolder
+		for i := range items {
+			item := &items[i]
+			ip := path.Join(folder.Path, item.Name)
+			var modified time.Time
+			if item.ModifiedAt != nil {
+				modified = time.Time(*item.ModifiedAt)
+			}
+			if !opts.Filter.Match(ip, item.IsFolder(), item.Size, modified) {
+				continue
+			}
+
+			mu.Lock()
+			if firstErr != nil {
+				mu.Unlock()
+				return nil, firstErr
+			}
+			err := fn(ip, item)
+			mu.Unlock()
+
+			if err == SkipFolder && item.IsFolder() {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if item.IsFolder() {
+				subs = append(subs, CrawlFolder{Id: item.Id, Path: ip})
+			}
+		}
+		return subs, nil
+	}
+
+	// wait blocks until the next listing request may be sent.
+	wait := func() error {
+		if throttle == nil {
+			return nil
+		}
+		select {
+		case <-throttle:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	visit = func(folder CrawlFolder) {
+		defer wg.Done()
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fail(ctx.Err())
+			return
+		}
+		defer func() { <-sem }()
+		if err := wait(); err != nil {
+			fail(err)
+			return
+		}
+
+		params := url.Values{"usemarker": {"true"}, "fields": {itemFields}}
+		rawurl := fmt.Sprintf("folders/%s/items", folder.Id)
+		err := box.listPages(ctx, rawurl, params, nil, folder.Marker, func(entries json.RawMessage, next string) error {
+			var items []Entity
+			if err := box.decode(entries, &items); err != nil {
+				return err
+			}
+			subs, err := page(folder, items)
+			if err != nil {
+				return err
+			}
+			if opts.Store != nil {
+				// Record the sub folders before moving past the page
+				// so that none is lost if the walk is interrupted.
+				if err = opts.Store.Add(subs); err != nil {
+					return err
+				}
+				if next != "" {
+					err = opts.Store.SetMarker(folder.Id, next)
+				} else {
+					err = opts.Store.Done(folder.Id)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			for _, sub := range subs {
+				wg.Add(1)
+				go visit(sub)
+			}
+			if next != "" {
+				return wait()
+			}
+			return nil
+		})
+		if err != nil {
+			fail(err)
+		}
+	}
+
+	for _, folder := range start {
+		wg.Add(1)
+		go visit(folder)
+	}
+	wg.Wait()
+	return firstErr
+}
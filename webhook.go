@@ -0,0 +1,201 @@
+package box
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Webhook triggers of sign requests.
+const (
+	SIGN_REQUEST_COMPLETED = "SIGN_REQUEST.COMPLETED" // All signers signed the document.
+	SIGN_REQUEST_DECLINED  = "SIGN_REQUEST.DECLINED"  // A signer declined to sign.
+	SIGN_REQUEST_EXPIRED   = "SIGN_REQUEST.EXPIRED"   // The sign request expired before completion.
+)
+
+// WebhookEvent is the payload Box posts to a webhook address. Source
+// and AdditionalInfo depend on the trigger and are kept raw, use the
+// typed accessors to decode them.
+type WebhookEvent struct {
+	Id             string          `json:"id,omitempty"`              // The id of the event.
+	Type           string          `json:"type,omitempty"`            // Type of the object, always webhook_event.
+	CreatedAt      *BoxTime        `json:"created_at,omitempty"`      // When the event happened.
+	Trigger        string          `json:"trigger,omitempty"`         // What triggered the event, e.g. SIGN_REQUEST.COMPLETED.
+	Webhook        *Entity         `json:"webhook,omitempty"`         // The webhook the event was sent for.
+	CreatedBy      *Entity         `json:"created_by,omitempty"`      // The user who caused the event.
+	Source         json.RawMessage `json:"source,omitempty"`          // The item the event happened on.
+	AdditionalInfo json.RawMessage `json:"additional_info,omitempty"` // Trigger specific details.
+}
+
+// SignRequestEvent holds the details of a sign request webhook event.
+type SignRequestEvent struct {
+	Trigger       string     `json:"-"`                         // One of the SIGN_REQUEST triggers.
+	Source        *File      `json:"-"`                         // The document that was sent for signature.
+	SignRequestId string     `json:"sign_request_id,omitempty"` // The id of the sign request.
+	SignFiles     *SignFiles `json:"sign_files,omitempty"`      // The signed documents, on completion.
+}
+
+type SignFiles struct {
+	Files              []File `json:"files,omitempty"`                 // The signed documents.
+	IsReadyForDownload bool   `json:"is_ready_for_download,omitempty"` // Whether the signed documents can be downloaded yet.
+}
+
+// ParseWebhookEvent decodes the body of a webhook request. The
+// signature of the request should be verified before trusting it, see
+// VerifyWebhook.
+func ParseWebhookEvent(body []byte) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// File decodes the source of the event as a file, e.g. for FILE
+// triggers, with its FileVersion.
+func (e *WebhookEvent) File() (*File, error) {
+	if len(e.Source) == 0 {
+		return nil, errors.New("Event has no source")
+	}
+	var src Entity
+	if err := json.Unmarshal(e.Source, &src); err != nil {
+		return nil, err
+	}
+	if !src.IsFile() {
+		return nil, errors.New("Event source is not a file")
+	}
+	var f File
+	if err := json.Unmarshal(e.Source, &f); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// IsSignRequest checks if the event was triggered by a sign request.
+func (e *WebhookEvent) IsSignRequest() bool {
+	return strings.HasPrefix(e.Trigger, "SIGN_REQUEST.")
+}
+
+// SignRequest decodes the details of a sign request event.
+func (e *WebhookEvent) SignRequest() (*SignRequestEvent, error) {
+	if !e.IsSignRequest() {
+		return nil, errors.New("Event is not a sign request event")
+	}
+	sr := SignRequestEvent{Trigger: e.Trigger}
+	if len(e.AdditionalInfo) > 0 {
+		if err := json.Unmarshal(e.AdditionalInfo, &sr); err != nil {
+			return nil, err
+		}
+	}
+	if len(e.Source) > 0 {
+		sr.Source = &File{}
+		if err := json.Unmarshal(e.Source, sr.Source); err != nil {
+			return nil, err
+		}
+	}
+	return &sr, nil
+}
+
+// Event returns the webhook event as an entry of the events stream, so
+// that webhooks and polling can be consumed the same way. The trigger
+// becomes the event type.
+func (e *WebhookEvent) Event() *Event {
+	return &Event{
+		Type:      "event",
+		EventId:   e.Id,
+		EventType: e.Trigger,
+		CreatedAt: e.CreatedAt,
+		CreatedBy: e.CreatedBy,
+		Source:    e.Source,
+	}
+}
+
+// webhookMaxAge is how old a webhook delivery may be by default.
+const webhookMaxAge = 10 * time.Minute
+
+// VerifyWebhook checks the signature of a webhook request with the
+// primary and secondary signature keys of the app, either of which may
+// be empty while keys are rotated. Deliveries older than maxAge, 10
+// minutes if zero, are rejected to prevent replays.
+func VerifyWebhook(header http.Header, body []byte, primaryKey, secondaryKey string, maxAge time.Duration) error {
+	if maxAge <= 0 {
+		maxAge = webhookMaxAge
+	}
+	if header.Get("Box-Signature-Version") != "1" || header.Get("Box-Signature-Algorithm") != "HmacSHA256" {
+		return errors.New("Unsupported webhook signature")
+	}
+	timestamp := header.Get("Box-Delivery-Timestamp")
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return errors.New("Invalid webhook delivery timestamp")
+	}
+	if time.Since(t) > maxAge {
+		return errors.New("Webhook delivery is too old")
+	}
+	if validSignature(primaryKey, body, timestamp, header.Get("Box-Signature-Primary")) ||
+		validSignature(secondaryKey, body, timestamp, header.Get("Box-Signature-Secondary")) {
+		return nil
+	}
+	return errors.New("Invalid webhook signature")
+}
+
+// validSignature checks the base64 HMAC-SHA256 of the body followed by
+// the timestamp.
+func validSignature(key string, body []byte, timestamp, signature string) bool {
+	if key == "" || signature == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	mac.Write([]byte(timestamp))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// maxWebhookBody bounds the size of the webhook payloads read.
+const maxWebhookBody = 1 << 20
+
+// WebhookHandler is an http.Handler receiving the webhooks of Box. It
+// verifies their signature and publishes the events to Sink, so they
+// can be consumed like the events of a Watcher, e.g. from an EventChan.
+// Unverified requests are refused. When Sink fails the request fails
+// too and Box delivers the event again later.
+type WebhookHandler struct {
+	PrimaryKey   string        // The primary signature key of the app.
+	SecondaryKey string        // The secondary signature key of the app.
+	MaxAge       time.Duration // How old a delivery may be, 10 minutes if zero.
+	Sink         EventSink     // Where the events are published.
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := VerifyWebhook(r.Header, body, h.PrimaryKey, h.SecondaryKey, h.MaxAge); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	event, err := ParseWebhookEvent(body)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := h.Sink.Publish(r.Context(), event.Event()); err != nil {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
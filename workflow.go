@@ -0,0 +1,118 @@
+package box
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Workflow is a Box Relay workflow configured on a folder.
+type Workflow struct {
+	Id          string `json:"id,omitempty"`          // The id of the workflow.
+	Type        string `json:"type,omitempty"`        // Type of the object, always workflow.
+	Name        string `json:"name,omitempty"`        // The name of the workflow.
+	Description string `json:"description,omitempty"` // The description of the workflow.
+	Enabled     bool   `json:"is_enabled,omitempty"`  // Whether the workflow is enabled.
+	Flows       []Flow `json:"flows,omitempty"`       // The flows that make up the workflow.
+}
+
+// Flow is a single trigger and its outcomes inside a workflow.
+type Flow struct {
+	Id        string    `json:"id,omitempty"`         // The id of the flow.
+	Type      string    `json:"type,omitempty"`       // Type of the object, always flow.
+	Trigger   *Trigger  `json:"trigger,omitempty"`    // What starts the flow.
+	Outcomes  []Outcome `json:"outcomes,omitempty"`   // What happens once the flow is started.
+	CreatedAt *BoxTime  `json:"created_at,omitempty"` // When the flow was created.
+	CreatedBy *Entity   `json:"created_by,omitempty"` // The user who created the flow.
+}
+
+type Trigger struct {
+	Type        string `json:"type,omitempty"`         // Type of the object, always trigger.
+	TriggerType string `json:"trigger_type,omitempty"` // The kind of trigger, e.g. WORKFLOW_MANUAL_START.
+}
+
+type Outcome struct {
+	Id         string `json:"id,omitempty"`          // The id of the outcome.
+	Type       string `json:"type,omitempty"`        // Type of the object, always outcome.
+	Name       string `json:"name,omitempty"`        // The name of the outcome.
+	ActionType string `json:"action_type,omitempty"` // The action taken by the outcome.
+}
+
+// workflowParameters is the request body starting a flow.
+type workflowParameters struct {
+	Type   string   `json:"type"`
+	Flow   *Entity  `json:"flow"`
+	Files  []Entity `json:"files"`
+	Folder *Entity  `json:"folder"`
+}
+
+// Workflows returns the workflows configured on the folder. Note that
+// only Id is required apriori.
+func (f *Folder) Workflows(ctx context.Context, box *Box, options ...RequestOption) ([]Workflow, error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using Workflows")
+	}
+	var all []Workflow
+	params := url.Values{"folder_id": {f.Id}}
+	err := box.listAll(ctx, "workflows", params, nil, func(entries json.RawMessage) error {
+		var page []Workflow
+		err := box.decode(entries, &page)
+		all = append(all, page...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// WorkflowsPage returns a single page of the workflows configured on
+// the folder with its paging information. Note that only Id is
+// required apriori.
+func (f *Folder) WorkflowsPage(ctx context.Context, box *Box, opts *ListOptions, options ...RequestOption) (*ListResult[Workflow], error) {
+	ctx = withCall(ctx, options)
+	if f.Id == "" {
+		return nil, errors.New("Empty id while using WorkflowsPage")
+	}
+	if err := opts.check(); err != nil {
+		return nil, err
+	}
+	params := opts.markerValues()
+	params.Set("folder_id", f.Id)
+	return listPage[Workflow](ctx, box, "workflows", params, nil)
+}
+
+// Start starts the given flow of the workflow for the files, which
+// must be inside folder. The workflow must be configured with a
+// manual start trigger. Note that only Id is required apriori for the
+// workflow, flow, folder and files.
+func (w *Workflow) Start(ctx context.Context, box *Box, flow *Flow, folder *Folder, files []*File, options ...RequestOption) error {
+	ctx = withCall(ctx, options)
+	if w.Id == "" || flow.Id == "" || folder.Id == "" {
+		return errors.New("Empty id while using Start")
+	}
+	if len(files) == 0 {
+		return errors.New("No files while using Start")
+	}
+
+	params := workflowParameters{
+		Type:   "workflow_parameters",
+		Flow:   &Entity{Id: flow.Id, Type: "flow"},
+		Folder: &Entity{Id: folder.Id, Type: "folder"},
+	}
+	for _, file := range files {
+		if file.Id == "" {
+			return errors.New("Empty file id while using Start")
+		}
+		params.Files = append(params.Files, Entity{Id: file.Id, Type: "file"})
+	}
+	reqBody, _ := json.Marshal(params)
+
+	rawurl := fmt.Sprintf("workflows/%s/start", w.Id)
+	_, err := box.doRequest(ctx, "POST", rawurl, nil, reqBody)
+
+	return err
+}